@@ -89,8 +89,8 @@ func (crud *Crud) TaskPermission(taskType string) mcresponse.ResponseMessage {
 			Value:   nil,
 		})
 	}
-	// validate task (roleServices) permission, for non-admin users
-	if !isAdmin && len(roleServices) < 1 {
+	// validate task (roleServices) permission, for non-admin users, without record-ids (ownership) to check
+	if !isAdmin && len(roleServices) < 1 && len(crud.RecordIds) < 1 {
 		return mcresponse.GetResMessage("unAuthorized", mcresponse.ResponseMessageOptions{
 			Message: "You are not authorized to perform the requested action/task",
 			Value:   nil,
@@ -101,17 +101,16 @@ func (crud *Crud) TaskPermission(taskType string) mcresponse.ResponseMessage {
 	accessUserId := accessRec.UserId
 	recordIds := crud.RecordIds
 	if len(recordIds) > 0 && accessUserId != "" && accessRec.IsActive {
-		// SQL script
-		sqlScript := fmt.Sprintf("SELECT id FROM %v WHERE id IN ($1) AND created_by = $2", crud.TableName)
-		inValues := ""
-		idLen := len(recordIds)
-		for idCount, id := range recordIds {
-			inValues += "'" + id + "'"
-			if idLen > 1 && idCount < idLen-1 {
-				inValues += ", "
-			}
+		// SQL script, recordIds as a single array-value
+		sqlScript := fmt.Sprintf("SELECT id FROM %v WHERE id = ANY($1) AND created_by = $2", helper.QuoteTableName(crud.TableName))
+		idsParam, idsErr := helper.ComputeIdsParam(recordIds)
+		if idsErr != nil {
+			return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Invalid record-ids: %v", idsErr.Error()),
+				Value:   nil,
+			})
 		}
-		rows, err := crud.AppDb.Query(context.Background(), sqlScript, inValues, accessUserId)
+		rows, err := crud.AppDb.Query(context.Background(), sqlScript, idsParam, accessUserId)
 		if err != nil {
 			errMsg := fmt.Sprintf("Db query Error: %v", err.Error())
 			return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
@@ -136,10 +135,10 @@ func (crud *Crud) TaskPermission(taskType string) mcresponse.ResponseMessage {
 
 	// filter the roleServices by categories ("collection | table" or "record | document")
 	collTabFunc := func(item types.RoleServiceType) bool {
-		return item.ServiceCategory == tableId
+		return item.ServiceId == tableId
 	}
 	recordFunc := func(item types.RoleServiceType) bool {
-		return helper.ArrayStringContains(recordIds, item.ServiceCategory)
+		return helper.ArrayStringContains(recordIds, item.ServiceId)
 	}
 
 	var (
@@ -291,7 +290,7 @@ func (crud *Crud) TaskPermission(taskType string) mcresponse.ResponseMessage {
 	if !taskPermitted {
 		return mcresponse.GetResMessage("unAuthorized", mcresponse.ResponseMessageOptions{
 			Message: "You are not authorized to perform the requested action/task.",
-			Value:   TaskPermissionType{
+			Value: TaskPermissionType{
 				Ok: taskPermitted,
 			},
 		})
@@ -300,7 +299,7 @@ func (crud *Crud) TaskPermission(taskType string) mcresponse.ResponseMessage {
 	// if all went well
 	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
 		Message: "Action authorised / permitted.",
-		Value:   TaskPermissionType{
+		Value: TaskPermissionType{
 			Ok:       taskPermitted,
 			IsAdmin:  isAdmin,
 			IsActive: isActive,
@@ -346,7 +345,7 @@ func (crud *Crud) CheckTaskAccess() mcresponse.ResponseMessage {
 		serviceId string
 		category  string
 	)
-	serviceScript := fmt.Sprintf("SELECT id, category from %v WHERE name=$1", crud.ServiceTable)
+	serviceScript := fmt.Sprintf("SELECT id, category FROM %v WHERE name=$1", helper.QuoteTableName(crud.ServiceTable))
 	serviceRow := crud.AccessDb.QueryRow(context.Background(), serviceScript, crud.TableName)
 	// check error
 	if err := serviceRow.Scan(&serviceId, &category); err != nil {
@@ -357,7 +356,7 @@ func (crud *Crud) CheckTaskAccess() mcresponse.ResponseMessage {
 	}
 	// if permitted, include table/collId and recordIds in serviceIds
 	tableId := ""
-	serviceIds := crud.RecordIds
+	serviceIds := append([]string{}, crud.RecordIds...)
 	catLowercase := strings.ToLower(category)
 	if catLowercase == "table" || catLowercase == "collection" {
 		tableId = serviceId
@@ -396,18 +395,13 @@ func (crud *Crud) CheckTaskAccess() mcresponse.ResponseMessage {
 // GetRoleServices method process and returns the permission to user / user-group for the specified service items
 func (crud *Crud) GetRoleServices(accessDb *pgxpool.Pool, roleTable string, groupId string, serviceIds []string) ([]types.RoleServiceType, error) {
 	var roleServices []types.RoleServiceType
-	roleScript := fmt.Sprintf("SELECT id, service_id, service_category, can_read, can_create, can_delete, can_update from %v WHERE service_id IN ($1) AND group_id=$2 AND is_active=$3", roleTable)
-	// where-in-values
-	inValues := ""
-	idLen := len(serviceIds)
-	for idCount, id := range serviceIds {
-		inValues += "'" + id + "'"
-		if idLen > 1 && idCount < idLen-1 {
-			inValues += ", "
-		}
+	// serviceIds as a single array-value
+	roleScript := fmt.Sprintf("SELECT id, service_id, service_category, can_read, can_create, can_delete, can_update FROM %v WHERE service_id = ANY($1) AND group_id=$2 AND is_active=$3", helper.QuoteTableName(roleTable))
+	idsParam, err := helper.ComputeIdsParam(serviceIds)
+	if err != nil {
+		return roleServices, err
 	}
-
-	rows, err := accessDb.Query(context.Background(), roleScript, inValues, groupId, true)
+	rows, err := accessDb.Query(context.Background(), roleScript, idsParam, groupId, true)
 	if err != nil {
 		//errMsg := fmt.Sprintf("Db query Error: %v", err.Error())
 		return roleServices, errors.New(fmt.Sprintf("%v", err.Error()))
@@ -438,7 +432,7 @@ func (crud *Crud) GetRoleServices(accessDb *pgxpool.Pool, roleTable string, grou
 func (crud *Crud) CheckUserAccess() mcresponse.ResponseMessage {
 	// validate current user active status: by token (API) and user/loggedIn-status
 	// get the accessKey information for the user
-	accessScript := fmt.Sprintf("SELECT expire from %v WHERE user_id=$1 AND token=$2 AND login_name=$3", crud.AccessTable)
+	accessScript := fmt.Sprintf("SELECT expire FROM %v WHERE user_id=$1 AND token=$2 AND login_name=$3", helper.QuoteTableName(crud.AccessTable))
 	rowAccess := crud.AccessDb.QueryRow(context.Background(), accessScript, crud.UserInfo.UserId, crud.UserInfo.Token, crud.UserInfo.LoginName)
 	// check login-status/expiration
 	var accessExpire int64
@@ -463,7 +457,7 @@ func (crud *Crud) CheckUserAccess() mcresponse.ResponseMessage {
 		isAdmin  bool
 		isActive bool
 	)
-	userScript := fmt.Sprintf("SELECT id, groups, isAdmin, isActive from %v WHERE id=$1 AND is_active=$2", crud.UserTable)
+	userScript := fmt.Sprintf("SELECT id, groups, is_admin, is_active FROM %v WHERE id=$1 AND is_active=$2", helper.QuoteTableName(crud.UserTable))
 	rowUser := crud.AccessDb.QueryRow(context.Background(), userScript, crud.UserInfo.UserId, true)
 	if err := rowUser.Scan(&uId, &groups, &isAdmin, &isActive); err != nil {
 		return mcresponse.GetResMessage("unAuthorized", mcresponse.ResponseMessageOptions{
@@ -472,7 +466,7 @@ func (crud *Crud) CheckUserAccess() mcresponse.ResponseMessage {
 		})
 	}
 	// get default-group from user profile
-	pScript := fmt.Sprintf("SELECT group from %v WHERE user_id=$1 is_active=$2", crud.UserProfileTable)
+	pScript := fmt.Sprintf(`SELECT "group" FROM %v WHERE user_id=$1 AND is_active=$2`, helper.QuoteTableName(crud.UserProfileTable))
 	userProfile := crud.AccessDb.QueryRow(context.Background(), pScript, crud.UserInfo.UserId, true)
 	if err := userProfile.Scan(&group); err != nil {
 		return mcresponse.GetResMessage("unAuthorized", mcresponse.ResponseMessageOptions{
@@ -502,8 +496,8 @@ func (crud *Crud) CheckLoginStatus(params mctypes.UserInfoType) mcresponse.Respo
 	username := emailUsername.Username
 	var uId string
 	if email != "" {
-		query := fmt.Sprintf("SELECT id from $1 WHERE id=$2 AND email=$3")
-		row := crud.AccessDb.QueryRow(context.Background(), query, crud.UserTable, params.UserId, email)
+		query := fmt.Sprintf("SELECT id FROM %v WHERE id=$1 AND email=$2", helper.QuoteTableName(crud.UserTable))
+		row := crud.AccessDb.QueryRow(context.Background(), query, params.UserId, email)
 		err := row.Scan(&uId)
 		if err != nil {
 			return mcresponse.GetResMessage("unAuthorized", mcresponse.ResponseMessageOptions{
@@ -512,8 +506,8 @@ func (crud *Crud) CheckLoginStatus(params mctypes.UserInfoType) mcresponse.Respo
 			})
 		}
 	} else if username != "" {
-		query := fmt.Sprintf("SELECT id from $1 WHERE id=$2 AND username=$3")
-		row := crud.AccessDb.QueryRow(context.Background(), query, crud.UserTable, params.UserId, username)
+		query := fmt.Sprintf("SELECT id FROM %v WHERE id=$1 AND username=$2", helper.QuoteTableName(crud.UserTable))
+		row := crud.AccessDb.QueryRow(context.Background(), query, params.UserId, username)
 		err := row.Scan(&uId)
 		if err != nil {
 			return mcresponse.GetResMessage("unAuthorized", mcresponse.ResponseMessageOptions{
@@ -531,8 +525,8 @@ func (crud *Crud) CheckLoginStatus(params mctypes.UserInfoType) mcresponse.Respo
 
 	// check loginName, userId and token validity... from access_keys table
 	var expire int64
-	query := fmt.Sprintf("SELECT expire from $1 WHERE id=$2 AND login_name=$3 AND token=$4")
-	row := crud.AccessDb.QueryRow(context.Background(), query, crud.AccessTable, params.UserId, params.LoginName, params.Token)
+	query := fmt.Sprintf("SELECT expire FROM %v WHERE user_id=$1 AND login_name=$2 AND token=$3", helper.QuoteTableName(crud.AccessTable))
+	row := crud.AccessDb.QueryRow(context.Background(), query, params.UserId, params.LoginName, params.Token)
 	err := row.Scan(&expire)
 	if err != nil {
 		return mcresponse.GetResMessage("unAuthorized", mcresponse.ResponseMessageOptions{
@@ -542,8 +536,8 @@ func (crud *Crud) CheckLoginStatus(params mctypes.UserInfoType) mcresponse.Respo
 	}
 	if (time.Now().Unix() * 1000) > expire {
 		// Delete the expired access_keys | remove access-info from access_keys table
-		delQuery := fmt.Sprintf("DELETE FROM %v WHERE id=$1 AND token=$2", crud.AccessTable)
-		_, _ = crud.AppDb.Exec(context.Background(), delQuery, params.UserId, params.Token)
+		delQuery := fmt.Sprintf("DELETE FROM %v WHERE user_id=$1 AND token=$2", helper.QuoteTableName(crud.AccessTable))
+		_, _ = crud.AccessDb.Exec(context.Background(), delQuery, params.UserId, params.Token)
 		return mcresponse.GetResMessage("tokenExpired", mcresponse.ResponseMessageOptions{
 			Message: "Access expired: please login to continue",
 			Value:   nil,
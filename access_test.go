@@ -0,0 +1,184 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-08 | @Updated: 2021-01-08
+// @Company: mConnect.biz | @License: MIT
+// @Description: access/task-permission test cases, against a local fixture schema
+
+package mcorm
+
+import (
+	"context"
+	"fmt"
+	"github.com/abbeymart/mcdb"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/tasks"
+	"github.com/abbeymart/mcresponse"
+	"github.com/abbeymart/mctest"
+	"github.com/abbeymart/mctypes"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"testing"
+	"time"
+)
+
+// access fixture tables
+const (
+	accessTestUserTable    = "mc_test_users"
+	accessTestRoleTable    = "mc_test_roles"
+	accessTestServiceTable = "mc_test_services"
+	accessTestAccessTable  = "mc_test_access_keys"
+	accessTestProfileTable = "mc_test_user_profile"
+	accessTestRecordTable  = "mc_test_records"
+)
+
+// access fixture ids
+const (
+	accessTestAdminId   = "c85509ac-7373-464d-b667-425bb59b5738"
+	accessTestOwnerId   = "085f48c5-8763-4e22-a1c6-ac1a68ba07de"
+	accessTestRoleId    = "b2c5e6a1-2f4d-4a53-9a6f-5d6b1e8f3c21"
+	accessTestServiceId = "5b7d0f2c-9c1e-4e0a-8f3d-2a6b4c8e1d90"
+	accessTestRecordId1 = "6900d9f9-2ceb-450f-9a9e-527eb66c962f"
+	accessTestRecordId2 = "122d0f0e-3111-41a5-9103-24fa81004550"
+)
+
+var accessTestFixtureScripts = []string{
+	fmt.Sprintf(`CREATE TABLE %v (id uuid PRIMARY KEY, username varchar(100), email varchar(255), groups text[], is_admin boolean NOT NULL DEFAULT false, is_active boolean NOT NULL DEFAULT true)`, accessTestUserTable),
+	fmt.Sprintf(`CREATE TABLE %v (user_id uuid NOT NULL, "group" varchar(100), is_active boolean NOT NULL DEFAULT true)`, accessTestProfileTable),
+	fmt.Sprintf(`CREATE TABLE %v (user_id uuid NOT NULL, login_name varchar(255), token varchar(255), expire bigint)`, accessTestAccessTable),
+	fmt.Sprintf(`CREATE TABLE %v (id uuid PRIMARY KEY, name varchar(100), category varchar(100))`, accessTestServiceTable),
+	fmt.Sprintf(`CREATE TABLE %v (id uuid PRIMARY KEY, service_id uuid, service_category varchar(100), group_id varchar(100), can_read boolean, can_create boolean, can_update boolean, can_delete boolean, is_active boolean)`, accessTestRoleTable),
+	fmt.Sprintf(`CREATE TABLE %v (id uuid PRIMARY KEY, name varchar(100), created_by uuid)`, accessTestRecordTable),
+	// fixture records
+	fmt.Sprintf(`INSERT INTO %v (id, username, email, groups, is_admin) VALUES ('%v', 'admin', 'admin@mconnect.biz', '{admin}', true), ('%v', 'owner', 'owner@mconnect.biz', '{owners}', false), ('%v', 'editor', 'editor@mconnect.biz', '{editors}', false)`, accessTestUserTable, accessTestAdminId, accessTestOwnerId, accessTestRoleId),
+	fmt.Sprintf(`INSERT INTO %v (user_id, "group") VALUES ('%v', 'admin'), ('%v', 'owners'), ('%v', 'editors')`, accessTestProfileTable, accessTestAdminId, accessTestOwnerId, accessTestRoleId),
+	fmt.Sprintf(`INSERT INTO %v (user_id, login_name, token, expire) VALUES ('%v', 'admin@mconnect.biz', 'admin-token', %v), ('%v', 'owner@mconnect.biz', 'owner-token', %v), ('%v', 'editor@mconnect.biz', 'editor-token', %v)`, accessTestAccessTable, accessTestAdminId, time.Now().Add(time.Hour).Unix()*1000, accessTestOwnerId, time.Now().Add(time.Hour).Unix()*1000, accessTestRoleId, time.Now().Add(time.Hour).Unix()*1000),
+	fmt.Sprintf(`INSERT INTO %v (id, name, category) VALUES ('%v', '%v', 'table')`, accessTestServiceTable, accessTestServiceId, accessTestRecordTable),
+	fmt.Sprintf(`INSERT INTO %v (id, service_id, service_category, group_id, can_read, can_create, can_update, can_delete, is_active) VALUES ('a0f3b1c2-6d7e-4f80-9a1b-2c3d4e5f6a7b', '%v', 'table', 'editors', true, true, true, false, true)`, accessTestRoleTable, accessTestServiceId),
+	fmt.Sprintf(`INSERT INTO %v (id, name, created_by) VALUES ('%v', 'record-1', '%v'), ('%v', 'record-2', '%v')`, accessTestRecordTable, accessTestRecordId1, accessTestOwnerId, accessTestRecordId2, accessTestOwnerId),
+}
+
+// setupAccessFixture drops and re-creates the access fixture tables and records
+func setupAccessFixture(dbc *pgxpool.Pool) error {
+	teardownAccessFixture(dbc)
+	for _, script := range accessTestFixtureScripts {
+		if _, err := dbc.Exec(context.Background(), script); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// teardownAccessFixture drops the access fixture tables
+func teardownAccessFixture(dbc *pgxpool.Pool) {
+	for _, table := range []string{accessTestUserTable, accessTestProfileTable, accessTestAccessTable, accessTestServiceTable, accessTestRoleTable, accessTestRecordTable} {
+		_, _ = dbc.Exec(context.Background(), fmt.Sprintf("DROP TABLE IF EXISTS %v", table))
+	}
+}
+
+func TestAccess(t *testing.T) {
+	myDb := mcdb.DbConfig{
+		DbType:   "postgres",
+		Host:     "localhost",
+		Username: "postgres",
+		Password: "ab12testing",
+		Port:     5432,
+		DbName:   "mcdev",
+		Filename: "testdb.db",
+		PoolSize: 20,
+		Url:      "localhost:5432",
+	}
+	myDb.Options = mcdb.DbConnectOptions{}
+
+	// db-connection
+	dbc, err := myDb.OpenPgxDbPool()
+	// defer dbClose
+	defer myDb.ClosePgxDbPool()
+
+	// check db-connection-error
+	if err != nil {
+		t.Skipf("*****db-connection-error: %v", err.Error())
+	}
+
+	if err = setupAccessFixture(dbc.DbConn); err != nil {
+		t.Skipf("*****access-fixture-error: %v", err.Error())
+	}
+	defer teardownAccessFixture(dbc.DbConn)
+
+	accessOptions := types.CrudOptionsType{
+		AccessDb:         dbc.DbConn,
+		UserTable:        accessTestUserTable,
+		RoleTable:        accessTestRoleTable,
+		ServiceTable:     accessTestServiceTable,
+		AccessTable:      accessTestAccessTable,
+		UserProfileTable: accessTestProfileTable,
+	}
+	accessCrud := func(userId string, loginName string, token string, recordIds []string) *Crud {
+		return NewCrud(types.CrudParamsType{
+			AppDb:     dbc.DbConn,
+			TableName: accessTestRecordTable,
+			UserInfo: mctypes.UserInfoType{
+				UserId:    userId,
+				LoginName: loginName,
+				Token:     token,
+			},
+			RecordIds: recordIds,
+		}, accessOptions)
+	}
+	taskPermission := func(res mcresponse.ResponseMessage) TaskPermissionType {
+		value, _ := res.Value.(TaskPermissionType)
+		return value
+	}
+
+	mctest.McTest(mctest.OptionValue{
+		Name: "should check login-status for a valid user and access-token:",
+		TestFunc: func() {
+			crud := accessCrud(accessTestOwnerId, "owner@mconnect.biz", "owner-token", nil)
+			res := crud.CheckLoginStatus(crud.UserInfo)
+			mctest.AssertEquals(t, res.Code, "success", "response-code should be: success")
+			res = crud.CheckLoginStatus(mctypes.UserInfoType{UserId: accessTestOwnerId, LoginName: "owner@mconnect.biz", Token: "invalid-token"})
+			mctest.AssertEquals(t, res.Code, "unAuthorized", "response-code should be: unAuthorized")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should permit update and delete tasks to the records' owner:",
+		TestFunc: func() {
+			crud := accessCrud(accessTestOwnerId, "owner@mconnect.biz", "owner-token", []string{accessTestRecordId1, accessTestRecordId2})
+			res := crud.TaskPermission(tasks.Update)
+			mctest.AssertEquals(t, res.Code, "success", "response-code should be: success")
+			mctest.AssertEquals(t, taskPermission(res).Ok, true, "task-permission should be: true")
+			mctest.AssertEquals(t, taskPermission(res).IsAdmin, false, "admin-status should be: false")
+			res = crud.TaskPermission(tasks.Delete)
+			mctest.AssertEquals(t, res.Code, "success", "response-code should be: success")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should permit table-level tasks by role and deny tasks without the role permission:",
+		TestFunc: func() {
+			crud := accessCrud(accessTestRoleId, "editor@mconnect.biz", "editor-token", []string{accessTestRecordId1, accessTestRecordId2})
+			res := crud.TaskPermission(tasks.Update)
+			mctest.AssertEquals(t, res.Code, "success", "response-code should be: success")
+			mctest.AssertEquals(t, taskPermission(res).Group, "editors", "user-group should be: editors")
+			res = crud.TaskPermission(tasks.Read)
+			mctest.AssertEquals(t, res.Code, "success", "response-code should be: success")
+			res = crud.TaskPermission(tasks.Delete)
+			mctest.AssertEquals(t, res.Code, "unAuthorized", "response-code should be: unAuthorized")
+			mctest.AssertEquals(t, taskPermission(res).Ok, false, "task-permission should be: false")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should permit all tasks to the admin user:",
+		TestFunc: func() {
+			crud := accessCrud(accessTestAdminId, "admin@mconnect.biz", "admin-token", []string{accessTestRecordId1, accessTestRecordId2})
+			res := crud.TaskPermission(tasks.Delete)
+			mctest.AssertEquals(t, res.Code, "success", "response-code should be: success")
+			mctest.AssertEquals(t, taskPermission(res).IsAdmin, true, "admin-status should be: true")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should deny tasks to a user with an invalid access-token:",
+		TestFunc: func() {
+			crud := accessCrud(accessTestOwnerId, "owner@mconnect.biz", "invalid-token", []string{accessTestRecordId1})
+			res := crud.TaskPermission(tasks.Update)
+			mctest.AssertEquals(t, res.Code, "unAuthorized", "response-code should be: unAuthorized")
+		},
+	})
+
+	mctest.PostTestResult()
+}
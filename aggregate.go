@@ -0,0 +1,113 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-13 | @Updated: 2021-01-13
+// @Company: mConnect.biz | @License: MIT
+// @Description: aggregate / group-by query record(s)
+
+package mcorm
+
+import (
+	"context"
+	"fmt"
+	"github.com/abbeymart/mcauditlog"
+	"github.com/abbeymart/mcorm/helper"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/tasks"
+	"github.com/abbeymart/mcresponse"
+)
+
+// Aggregate method computes the aggregates (count, sum, avg, min or max) of the records that met the specified
+// record-id(s) or query-params (all records, if not specified), grouped by the group-fields and constrained by
+// optional having-params, skip and limit parameters. Each grouped-record is a map of the group-field-values
+// and the aggregate-values, by alias
+func (crud *Crud) Aggregate() mcresponse.ResponseMessage {
+	// where-query, by record-ids or query-params
+	var (
+		whereRes types.WhereQueryResponseType
+		err      error
+	)
+	if len(crud.RecordIds) > 0 {
+		whereRes, err = helper.ComputeWhereQueryByIds(crud.RecordIds, 0)
+	} else if len(crud.QueryParams) > 0 {
+		whereRes, err = helper.ComputeWhereQuery(crud.QueryParams, 0)
+	}
+	// exclude the soft-deleted records
+	whereRes, err = crud.activeWhere(whereRes, err)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing where-query: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	aggregateQuery, err := helper.ComputeAggregateQuery(crud.TableName, crud.AggregateParams, whereRes, crud.RecordDesc)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing aggregate-query: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	getQuery := aggregateQuery.SelectQuery
+	// include options: skip & limit
+	if crud.Limit > 0 {
+		getQuery += fmt.Sprintf(" LIMIT %v", crud.Limit)
+	}
+	if crud.Skip > 0 {
+		getQuery += fmt.Sprintf(" OFFSET %v", crud.Skip)
+	}
+	// perform crud-task action
+	rows, qRowErr := crud.AppDb.Query(context.Background(), getQuery, aggregateQuery.FieldValues...)
+	if qRowErr != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Db query Error: %v", qRowErr.Error()),
+			Value:   nil,
+		})
+	}
+	defer rows.Close()
+	// check rows count
+	var rowCount = 0
+	var getResults []interface{}
+	for rows.Next() {
+		rowValues, rowErr := rows.Values()
+		if rowErr != nil {
+			return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error reading/getting records[row-values]: %v", rowErr.Error()),
+				Value:   nil,
+			})
+		}
+		getResult := map[string]interface{}{}
+		for i, field := range rows.FieldDescriptions() {
+			getResult[string(field.Name)] = helper.ComputeRowValue(rowValues[i])
+		}
+		getResults = append(getResults, getResult)
+		rowCount += 1
+	}
+
+	if rowErr := rows.Err(); rowErr != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error reading/getting records: %v", rowErr.Error()),
+			Value:   nil,
+		})
+	}
+
+	// perform audit-log
+	logMessage := ""
+	if crud.LogRead {
+		auditInfo := mcauditlog.PgxAuditLogOptionsType{
+			TableName:  crud.TableName,
+			LogRecords: crud.AggregateParams,
+		}
+		if logRes, logErr := crud.TransLog.AuditLog(tasks.Read, crud.UserInfo.UserId, auditInfo); logErr != nil {
+			logMessage = fmt.Sprintf("Audit-log-error: %v", logErr.Error())
+		} else {
+			logMessage = fmt.Sprintf("Audit-log-code: %v | Message: %v", logRes.Code, logRes.Message)
+		}
+	}
+
+	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
+		Message: logMessage,
+		Value: types.CrudResultType{
+			QueryParam:   crud.QueryParams,
+			RecordIds:    crud.RecordIds,
+			RecordCount:  rowCount,
+			TableRecords: getResults,
+		},
+	})
+}
@@ -0,0 +1,301 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-17 | @Updated: 2021-01-17
+// @Company: mConnect.biz | @License: MIT
+// @Description: mcorm command-line tool: migrations, schema diff/create, seeds and code generation
+
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/abbeymart/mcdb"
+	"github.com/abbeymart/mcorm"
+	"github.com/abbeymart/mcorm/helper"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"io"
+	"os"
+	"strconv"
+)
+
+// Usage describes the mcorm commands
+const Usage = `Usage: mcorm [db-flags] <command> [command-flags]
+
+Commands:
+  migrate up [-allow-out-of-order]
+                                   apply the pending migrations, including those lower than the last applied version
+  migrate down [-steps n]          roll-back the last applied migration(s) | default steps: 1
+  migrate status                   list the applied and pending migrations
+  schema diff [-table name]        show the alter-table scripts, from the diff of the models and the live tables
+  schema create [-table name] [-dry-run]
+                                   create the model tables (if not exists), or show the create-table scripts
+  seed                             run the registered seeds
+  gen struct [-table name] [-package name] [-from-db]
+                                   generate the Go structs of the models, or of the live table (-from-db)
+
+Db-flags (default: env):
+  -host (MCORM_DB_HOST), -port (MCORM_DB_PORT), -user (MCORM_DB_USERNAME), -password (MCORM_DB_PASSWORD),
+  -dbname (MCORM_DB_NAME), -poolsize (MCORM_DB_POOLSIZE), -sslmode (MCORM_DB_SSLMODE), -sslcert (MCORM_DB_SSLCERT)
+  DATABASE_URL, if set, overrides the db-flags
+`
+
+// envValue returns the env-value of the key, or the default-value
+func envValue(key string, defaultValue string) string {
+	if val, ok := os.LookupEnv(key); ok {
+		return val
+	}
+	return defaultValue
+}
+
+// ComputeDbConfig computes the db-configuration (mcdb.DbConfig) from the db-flags, with the env default-values,
+// and returns the remaining (command) args
+func ComputeDbConfig(args []string, out io.Writer) (mcdb.DbConfig, []string, error) {
+	flags := flag.NewFlagSet("mcorm", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() { _, _ = fmt.Fprint(out, Usage) }
+	port, err := strconv.ParseUint(envValue("MCORM_DB_PORT", "5432"), 10, 32)
+	if err != nil {
+		return mcdb.DbConfig{}, nil, errors.New(fmt.Sprintf("Invalid MCORM_DB_PORT: %v", err.Error()))
+	}
+	poolSize, err := strconv.ParseUint(envValue("MCORM_DB_POOLSIZE", "10"), 10, 32)
+	if err != nil {
+		return mcdb.DbConfig{}, nil, errors.New(fmt.Sprintf("Invalid MCORM_DB_POOLSIZE: %v", err.Error()))
+	}
+	dbConfig := mcdb.DbConfig{DbType: "postgres"}
+	flags.StringVar(&dbConfig.Host, "host", envValue("MCORM_DB_HOST", "localhost"), "db host")
+	dbPort := flags.Uint("port", uint(port), "db port")
+	flags.StringVar(&dbConfig.Username, "user", envValue("MCORM_DB_USERNAME", "postgres"), "db username")
+	flags.StringVar(&dbConfig.Password, "password", envValue("MCORM_DB_PASSWORD", ""), "db password")
+	flags.StringVar(&dbConfig.DbName, "dbname", envValue("MCORM_DB_NAME", ""), "db name")
+	flags.UintVar(&dbConfig.PoolSize, "poolsize", uint(poolSize), "db connection pool-size")
+	flags.StringVar(&dbConfig.SecureOption.SslMode, "sslmode", envValue("MCORM_DB_SSLMODE", "disable"), "db ssl-mode")
+	flags.StringVar(&dbConfig.SecureOption.SecureCert, "sslcert", envValue("MCORM_DB_SSLCERT", ""), "db ssl root-certificate")
+	if err = flags.Parse(args); err != nil {
+		return mcdb.DbConfig{}, nil, err
+	}
+	dbConfig.Port = uint32(*dbPort)
+	dbConfig.Options = mcdb.DbConnectOptions{}
+	return dbConfig, flags.Args(), nil
+}
+
+// ComputeModels returns the registered models, or the model of the table-name, if specified
+func ComputeModels(tableName string) ([]types.ModelType, error) {
+	models := mcorm.RegisteredModels()
+	if tableName == "" {
+		if len(models) < 1 {
+			return nil, errors.New("no registered models: register the models (mcorm.RegisterModels), from the init function of the models package")
+		}
+		return models, nil
+	}
+	for _, model := range models {
+		if model.TableName == tableName {
+			return []types.ModelType{model}, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("no registered model for the table: %v", tableName))
+}
+
+// Run runs the mcorm command of the args (without the program-name), and writes the results to out
+func Run(args []string, out io.Writer) error {
+	dbConfig, args, err := ComputeDbConfig(args, out)
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 || args[0] == "help" {
+		_, _ = fmt.Fprint(out, Usage)
+		return nil
+	}
+	// command and sub-command, e.g. migrate up
+	command, args := args[0], args[1:]
+	if (command == "migrate" || command == "schema" || command == "gen") && len(args) > 0 {
+		command, args = command+" "+args[0], args[1:]
+	}
+	flags := flag.NewFlagSet("mcorm "+command, flag.ContinueOnError)
+	flags.SetOutput(out)
+	tableName := flags.String("table", "", "model table-name | default: all registered models")
+	dryRun := flags.Bool("dry-run", false, "show the scripts, without executing them")
+	steps := flags.Int("steps", 1, "number of migrations to roll-back")
+	allowOutOfOrder := flags.Bool("allow-out-of-order", false, "apply the pending migrations lower than the last applied version")
+	packageName := flags.String("package", "models", "package-name of the generated structs")
+	fromDb := flags.Bool("from-db", false, "generate the struct of the live table (-table)")
+	if err = flags.Parse(args); err != nil {
+		return err
+	}
+	// commands without db-connection
+	switch command {
+	case "gen struct":
+		if !*fromDb {
+			return genStruct(nil, *tableName, *packageName, out)
+		}
+		if *tableName == "" {
+			return errors.New("gen struct -from-db: table-name (-table) is required")
+		}
+	case "schema create":
+		if *dryRun {
+			return schemaCreate(nil, *tableName, out)
+		}
+	case "migrate up", "migrate down", "migrate status", "schema diff", "seed":
+		break
+	default:
+		_, _ = fmt.Fprint(out, Usage)
+		return errors.New(fmt.Sprintf("unknown command: %v", command))
+	}
+	dbPool, err := dbConfig.OpenPgxDbPool()
+	if err != nil {
+		return err
+	}
+	defer dbConfig.ClosePgxDbPool()
+	appDb := dbPool.DbConn
+	switch command {
+	case "migrate up":
+		return migrateUp(appDb, *allowOutOfOrder, out)
+	case "migrate down":
+		return migrateDown(appDb, *steps, out)
+	case "migrate status":
+		return migrateStatus(appDb, out)
+	case "schema diff":
+		return schemaDiff(appDb, *tableName, out)
+	case "schema create":
+		return schemaCreate(appDb, *tableName, out)
+	case "gen struct":
+		return genStruct(appDb, *tableName, *packageName, out)
+	default:
+		return seed(appDb, out)
+	}
+}
+
+func migrateUp(appDb *pgxpool.Pool, allowOutOfOrder bool, out io.Writer) error {
+	migrator := mcorm.NewMigrator(appDb, mcorm.RegisteredMigrations()...)
+	migrator.AllowOutOfOrder = allowOutOfOrder
+	appliedMigrations, err := migrator.Up()
+	for _, migration := range appliedMigrations {
+		_, _ = fmt.Fprintf(out, "applied: %v - %v\n", migration.Version, migration.Name)
+	}
+	if err == nil && len(appliedMigrations) < 1 {
+		_, _ = fmt.Fprintln(out, "no pending migrations")
+	}
+	return err
+}
+
+func migrateDown(appDb *pgxpool.Pool, steps int, out io.Writer) error {
+	revertedMigrations, err := mcorm.NewMigrator(appDb, mcorm.RegisteredMigrations()...).Down(steps)
+	for _, migration := range revertedMigrations {
+		_, _ = fmt.Fprintf(out, "rolled-back: %v - %v\n", migration.Version, migration.Name)
+	}
+	if err == nil && len(revertedMigrations) < 1 {
+		_, _ = fmt.Fprintln(out, "no applied migrations")
+	}
+	return err
+}
+
+func migrateStatus(appDb *pgxpool.Pool, out io.Writer) error {
+	history, pendingMigrations, err := mcorm.NewMigrator(appDb, mcorm.RegisteredMigrations()...).Status()
+	if err != nil {
+		return err
+	}
+	for _, item := range history {
+		_, _ = fmt.Fprintf(out, "applied: %v - %v | %v\n", item.Version, item.Name, item.AppliedAt.Format("2006-01-02 15:04:05"))
+	}
+	outOfOrderMigrations := helper.ComputeOutOfOrderMigrations(pendingMigrations, history)
+	for _, migration := range pendingMigrations {
+		status := "pending"
+		for _, outOfOrder := range outOfOrderMigrations {
+			if outOfOrder.Version == migration.Version {
+				status = "pending (out-of-order)"
+			}
+		}
+		_, _ = fmt.Fprintf(out, "%v: %v - %v\n", status, migration.Version, migration.Name)
+	}
+	return nil
+}
+
+func schemaDiff(appDb *pgxpool.Pool, tableName string, out io.Writer) error {
+	models, err := ComputeModels(tableName)
+	if err != nil {
+		return err
+	}
+	for _, model := range models {
+		tableColumns, err := helper.GetTableColumns(model.TableName, appDb)
+		if err != nil {
+			return err
+		}
+		var diffQuery string
+		if len(tableColumns) < 1 {
+			diffQuery, err = helper.CreateTableQuery(model)
+		} else {
+			diffQuery, err = helper.CreateAlterTableQuery(model, appDb)
+		}
+		if err != nil {
+			return err
+		}
+		if diffQuery == "" {
+			diffQuery = "-- up-to-date"
+		}
+		_, _ = fmt.Fprintf(out, "-- table: %v\n%v\n", model.TableName, diffQuery)
+	}
+	return nil
+}
+
+// schemaCreate creates the model tables, or shows the create-table scripts, for nil appDb (dry-run)
+func schemaCreate(appDb *pgxpool.Pool, tableName string, out io.Writer) error {
+	models, err := ComputeModels(tableName)
+	if err != nil {
+		return err
+	}
+	for _, model := range models {
+		if appDb == nil {
+			createQuery, err := helper.CreateTableQuery(model)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(out, "-- table: %v\n%v\n", model.TableName, createQuery)
+			continue
+		}
+		if err = helper.CreateTable(model, appDb); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "created: %v\n", model.TableName)
+	}
+	return nil
+}
+
+func seed(appDb *pgxpool.Pool, out io.Writer) error {
+	seeds := mcorm.RegisteredSeeds()
+	if len(seeds) < 1 {
+		return errors.New("no registered seeds: register the seeds (mcorm.RegisterSeeds)")
+	}
+	for _, item := range seeds {
+		if err := item.Seed(appDb); err != nil {
+			return errors.New(fmt.Sprintf("seed [%v]: %v", item.Name, err.Error()))
+		}
+		_, _ = fmt.Fprintf(out, "seeded: %v\n", item.Name)
+	}
+	return nil
+}
+
+// genStruct generates the Go structs file of the models, or of the live table, for appDb (from-db)
+func genStruct(appDb *pgxpool.Pool, tableName string, packageName string, out io.Writer) error {
+	var models []types.ModelType
+	if appDb != nil {
+		tableColumns, err := helper.GetTableColumns(tableName, appDb)
+		if err != nil {
+			return err
+		}
+		model, err := helper.ComputeTableModel(tableName, tableColumns)
+		if err != nil {
+			return err
+		}
+		models = append(models, model)
+	} else {
+		var err error
+		if models, err = ComputeModels(tableName); err != nil {
+			return err
+		}
+	}
+	structFile, err := helper.GenerateStructFile(packageName, models)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprint(out, structFile)
+	return nil
+}
@@ -0,0 +1,71 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-17 | @Updated: 2021-01-17
+// @Company: mConnect.biz | @License: MIT
+// @Description: mcorm command-line tool test cases
+
+package cli
+
+import (
+	"bytes"
+	"github.com/abbeymart/mcorm"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"github.com/abbeymart/mctest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCli(t *testing.T) {
+	mcorm.RegisterModels(types.ModelType{
+		TableName: "mc_cli_services",
+		RecordDesc: types.RecordDescType{
+			"name": types.FieldDescType{FieldType: datatypes.String, FieldLength: 120},
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the db-config from the db-flags, with the env default-values:",
+		TestFunc: func() {
+			_ = os.Setenv("MCORM_DB_NAME", "mcdev")
+			_ = os.Setenv("MCORM_DB_PORT", "5433")
+			defer os.Unsetenv("MCORM_DB_NAME")
+			defer os.Unsetenv("MCORM_DB_PORT")
+			dbConfig, args, err := ComputeDbConfig([]string{"-host", "db.local", "-user", "mcadmin", "migrate", "up"}, &bytes.Buffer{})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, dbConfig.Host, "db.local", "host should be the flag-value")
+			mctest.AssertEquals(t, dbConfig.Username, "mcadmin", "username should be the flag-value")
+			mctest.AssertEquals(t, dbConfig.DbName, "mcdev", "db-name should be the env-value")
+			mctest.AssertEquals(t, dbConfig.Port, uint32(5433), "port should be the env-value")
+			mctest.AssertStrictEquals(t, args, []string{"migrate", "up"}, "command args should be: migrate up")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should show the create-table scripts of the registered models, for schema create -dry-run:",
+		TestFunc: func() {
+			out := &bytes.Buffer{}
+			err := Run([]string{"schema", "create", "-dry-run", "-table", "mc_cli_services"}, out)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, strings.HasPrefix(out.String(), "-- table: mc_cli_services\nCREATE TABLE IF NOT EXISTS \"mc_cli_services\""), true, "output should be the create-table script")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should generate the structs file of the registered models, for gen struct:",
+		TestFunc: func() {
+			out := &bytes.Buffer{}
+			err := Run([]string{"gen", "struct", "-table", "mc_cli_services", "-package", "app"}, out)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, strings.Contains(out.String(), "package app\n"), true, "output should be in the package: app")
+			mctest.AssertEquals(t, strings.Contains(out.String(), "type McCliServices struct {"), true, "output should be the struct of the model")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return error for unknown command and unregistered model-table:",
+		TestFunc: func() {
+			err := Run([]string{"schema", "drop"}, &bytes.Buffer{})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			err = Run([]string{"schema", "create", "-dry-run", "-table", "unknown_table"}, &bytes.Buffer{})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+
+	mctest.PostTestResult()
+}
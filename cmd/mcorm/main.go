@@ -0,0 +1,20 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-17 | @Updated: 2021-01-17
+// @Company: mConnect.biz | @License: MIT
+// @Description: mcorm command-line tool. The models, migrations and seeds are registered (mcorm.RegisterModels,
+// RegisterMigrations and RegisterSeeds) by the init function of the application package(s), blank-imported by a
+// copy of this main package, e.g. import _ "example.com/app/models"
+
+package main
+
+import (
+	"fmt"
+	"github.com/abbeymart/mcorm/cli"
+	"os"
+)
+
+func main() {
+	if err := cli.Run(os.Args[1:], os.Stdout); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "mcorm: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,138 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-12 | @Updated: 2021-01-12
+// @Company: mConnect.biz | @License: MIT
+// @Description: count / exists record(s)
+
+package mcorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/helper"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcresponse"
+)
+
+// Count method returns the count of records that met the specified record-id(s), or all records if not specified
+func (crud *Crud) Count() mcresponse.ResponseMessage {
+	whereRes := types.WhereQueryResponseType{}
+	if len(crud.RecordIds) > 0 {
+		var err error
+		whereRes, err = helper.ComputeWhereQueryByIds(crud.RecordIds, 0)
+		if err != nil {
+			return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error computing where-query: %v", err.Error()),
+				Value:   nil,
+			})
+		}
+	}
+	return crud.CountRecords(whereRes)
+}
+
+// CountByParam method returns the count of records that met the specified query-params or where conditions
+func (crud *Crud) CountByParam() mcresponse.ResponseMessage {
+	whereRes, err := helper.ComputeWhereQuery(crud.QueryParams, 0)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing where-query: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	return crud.CountRecords(whereRes)
+}
+
+// CountRecords method returns the count of records that met the where-query, in the CrudResultType value
+func (crud *Crud) CountRecords(whereRes types.WhereQueryResponseType) mcresponse.ResponseMessage {
+	// exclude the soft-deleted records
+	whereRes, err := crud.activeWhere(whereRes, nil)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing where-query: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	count, err := crud.ComputeCount(whereRes)
+	if err != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: err.Error(),
+			Value:   nil,
+		})
+	}
+	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
+		Message: "Records count completed successfully",
+		Value: types.CrudResultType{
+			QueryParam:  crud.QueryParams,
+			RecordIds:   crud.RecordIds,
+			RecordCount: count,
+			TotalCount:  count,
+		},
+	})
+}
+
+// Exists method determines if any record met the specified record-id(s) or, if not specified, the query-params
+func (crud *Crud) Exists() mcresponse.ResponseMessage {
+	var (
+		whereRes types.WhereQueryResponseType
+		err      error
+	)
+	if len(crud.RecordIds) > 0 {
+		whereRes, err = helper.ComputeWhereQueryByIds(crud.RecordIds, 0)
+	} else if len(crud.QueryParams) > 0 {
+		whereRes, err = helper.ComputeWhereQuery(crud.QueryParams, 0)
+	} else {
+		err = errors.New("record-ids or query-params are required")
+	}
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing where-query: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	// exclude the soft-deleted records
+	if whereRes, err = crud.activeWhere(whereRes, nil); err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing where-query: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	existsQuery, err := helper.ComputeExistsQuery(crud.TableName, whereRes)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing exists-query: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	var exists bool
+	if err = crud.AppDb.QueryRow(context.Background(), existsQuery.SelectQuery, existsQuery.FieldValues...).Scan(&exists); err != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Db query Error: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
+		Message: "Records exists-check completed successfully",
+		Value:   exists,
+	})
+}
+
+// ComputeCount method computes the count of records that met the where-query, or all records for empty where-query
+func (crud *Crud) ComputeCount(whereRes types.WhereQueryResponseType) (int, error) {
+	countQuery, err := helper.ComputeCountQuery(crud.TableName, whereRes)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("Error computing count-query: %v", err.Error()))
+	}
+	var count int
+	if err = crud.AppDb.QueryRow(context.Background(), countQuery.SelectQuery, countQuery.FieldValues...).Scan(&count); err != nil {
+		return 0, errors.New(fmt.Sprintf("Db query Error: %v", err.Error()))
+	}
+	return count, nil
+}
+
+// ComputeTotalCount method computes the total count of records that met the where-query, for paginated
+// (skip, limit or cursor) get-queries only. Returns 0 otherwise
+func (crud *Crud) ComputeTotalCount(whereRes types.WhereQueryResponseType) (int, error) {
+	if crud.Skip < 1 && crud.Limit < 1 && !crud.CursorPaging {
+		return 0, nil
+	}
+	return crud.ComputeCount(whereRes)
+}
@@ -8,7 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/abbeymart/mcauditlog"
+	"github.com/abbeymart/mcorm/helper"
 	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcresponse"
 )
 
 // Crud object / struct
@@ -20,6 +22,17 @@ type Crud struct {
 	HashKey        string // Unique for exactly the same query
 }
 
+// conflictError response-message, for the optimistic-concurrency (stale records) update conflicts
+func init() {
+	mcresponse.StdResMessages["conflictError"] = mcresponse.ResponseMessage{
+		Code:       "conflictError",
+		ResCode:    mcresponse.Conflict,
+		ResMessage: mcresponse.StatusText[mcresponse.Conflict],
+		Message:    "Update conflict: record(s) changed by another update, reload and retry",
+		Value:      "",
+	}
+}
+
 // NewCrud constructor returns a new crud-instance
 func NewCrud(params types.CrudParamsType, options types.CrudOptionsType) (crudInstance *Crud) {
 	crudInstance = &Crud{}
@@ -37,6 +50,10 @@ func NewCrud(params types.CrudParamsType, options types.CrudOptionsType) (crudIn
 	crudInstance.TaskType = params.TaskType
 	crudInstance.Skip = params.Skip
 	crudInstance.Limit = params.Limit
+	crudInstance.AggregateParams = params.AggregateParams
+	crudInstance.CursorPaging = params.CursorPaging
+	crudInstance.Cursor = params.Cursor
+	crudInstance.UpsertParams = params.UpsertParams
 
 	// crud options
 	crudInstance.MaxQueryLimit = options.MaxQueryLimit
@@ -46,6 +63,17 @@ func NewCrud(params types.CrudParamsType, options types.CrudOptionsType) (crudIn
 	crudInstance.UserTable = options.UserTable
 	crudInstance.UserProfileTable = options.UserProfileTable
 	crudInstance.ServiceTable = options.ServiceTable
+	crudInstance.RecordDesc = options.RecordDesc
+	crudInstance.PrimaryFields = options.PrimaryFields
+	crudInstance.UniqueFields = options.UniqueFields
+	crudInstance.CopyThreshold = options.CopyThreshold
+	crudInstance.CopyChunkSize = options.CopyChunkSize
+	crudInstance.CopyProgress = options.CopyProgress
+	crudInstance.VersionField = options.VersionField
+	crudInstance.ReturnRecord = options.ReturnRecord
+	crudInstance.SoftDeleteField = options.SoftDeleteField
+	crudInstance.IncludeDeleted = options.IncludeDeleted
+	crudInstance.ChildRelations = options.ChildRelations
 	crudInstance.AuditDb = options.AuditDb
 	crudInstance.AccessDb = options.AccessDb
 	crudInstance.LogCrud = options.LogCrud
@@ -55,12 +83,19 @@ func NewCrud(params types.CrudParamsType, options types.CrudOptionsType) (crudIn
 	crudInstance.LogDelete = options.LogDelete
 	crudInstance.CheckAccess = options.CheckAccess // Dec 09/2020: user to implement auth as a middleware
 	crudInstance.CacheExpire = options.CacheExpire // cache expire in secs
-	// Compute HashKey from TableName, QueryParams, SortParams, ProjectParams and RecordIds
+	// Compute HashKey from TableName, QueryParams, SortParams, ProjectParams, RecordIds and paging (skip & limit | cursor)
 	qParam, _ := json.Marshal(params.QueryParams)
 	sParam, _ := json.Marshal(params.SortParams)
 	pParam, _ := json.Marshal(params.ProjectParams)
 	dIds, _ := json.Marshal(params.RecordIds)
-	crudInstance.HashKey = params.TableName + string(qParam) + string(sParam) + string(pParam) + string(dIds)
+	crudInstance.HashKey = params.TableName + string(qParam) + string(sParam) + string(pParam) + string(dIds) +
+		fmt.Sprintf("skip:%v|limit:%v", params.Skip, params.Limit)
+	if params.CursorPaging {
+		crudInstance.HashKey += fmt.Sprintf("|cursor:%v", params.Cursor)
+	}
+	if options.IncludeDeleted {
+		crudInstance.HashKey += "|includeDeleted"
+	}
 
 	// Default values
 	if crudInstance.AuditTable == "" {
@@ -81,6 +116,12 @@ func NewCrud(params types.CrudParamsType, options types.CrudOptionsType) (crudIn
 	if crudInstance.ServiceTable == "" {
 		crudInstance.ServiceTable = "services"
 	}
+	if crudInstance.CopyThreshold < 1 {
+		crudInstance.CopyThreshold = helper.CopyThreshold
+	}
+	if crudInstance.CopyChunkSize < 1 {
+		crudInstance.CopyChunkSize = helper.CopyChunkSize
+	}
 	if crudInstance.AuditDb == nil {
 		crudInstance.AuditDb = crudInstance.AppDb
 	}
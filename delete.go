@@ -11,71 +11,171 @@ import (
 	"github.com/abbeymart/mccache"
 	"github.com/abbeymart/mcorm/helper"
 	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/ormActions"
 	"github.com/abbeymart/mcorm/types/tasks"
 	"github.com/abbeymart/mcresponse"
 )
 
-// DeleteById method deletes or removes record(s) by record-id(s)
+// DeleteById method deletes or removes record(s) by record-id(s). With the SoftDeleteField, the records are
+// soft-deleted (see RestoreById and PurgeById). With the ReturnRecord type, the deleted records are returned,
+// as TableRecords
 func (crud *Crud) DeleteById() mcresponse.ResponseMessage {
-	// compute delete query by record-ids
-	deleteQuery, dQErr := helper.ComputeDeleteQueryById(crud.TableName, crud.RecordIds)
+	// compute delete query by record-ids, soft-delete (update) query, with the SoftDeleteField
+	var deleteQuery types.DeleteQueryResponseType
+	var dQErr error
+	if crud.SoftDeleteField != "" {
+		deleteQuery, dQErr = crud.computeSoftDeleteQuery(helper.ComputeWhereQueryByIds(crud.RecordIds, 0))
+	} else {
+		deleteQuery, dQErr = helper.ComputeDeleteQueryById(crud.TableName, crud.RecordIds)
+	}
 	if dQErr != nil {
 		return mcresponse.GetResMessage("deleteError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Error computing delete-query: %v", dQErr.Error()),
 			Value:   nil,
 		})
 	}
-	commandTag, delErr := crud.AppDb.Exec(context.Background(), deleteQuery)
-	if delErr != nil {
+	// referential-integrity actions, of the child-relations
+	actionQueries, aQErr := helper.ComputeDeleteActionQueries(crud.TableName, crud.ChildRelations, deleteQuery.WhereQuery, crud.SoftDeleteField != "")
+	if aQErr != nil {
 		return mcresponse.GetResMessage("deleteError", mcresponse.ResponseMessageOptions{
-			Message: fmt.Sprintf("Error deleting record(s): %v", delErr.Error()),
+			Message: fmt.Sprintf("Error computing relation-actions: %v", aQErr.Error()),
 			Value:   nil,
 		})
 	}
-
-	// delete cache
-	_ = mccache.DeleteHashCache(crud.TableName, crud.HashKey, "hash")
-
-	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
-		Message: "Record(s) deleted successfully",
-		Value:   commandTag.Delete(),
-	})
+	return crud.deleteRecords(deleteQuery, actionQueries)
 }
 
-// DeleteByParam method deletes or removes record(s) by query-parameters or where conditions
+// DeleteByParam method deletes or removes record(s) by query-parameters or where conditions. With the
+// SoftDeleteField, the records are soft-deleted (see RestoreByParam and PurgeByParam). With the ReturnRecord type,
+// the deleted records are returned, as TableRecords
 func (crud *Crud) DeleteByParam() mcresponse.ResponseMessage {
-	// compute delete query by query-params
-	deleteQuery, dQErr := helper.ComputeDeleteQueryByParam(crud.TableName, crud.QueryParams)
+	// compute delete query by query-params, soft-delete (update) query, with the SoftDeleteField
+	var deleteQuery types.DeleteQueryResponseType
+	var dQErr error
+	if crud.SoftDeleteField != "" && len(crud.QueryParams) > 0 {
+		deleteQuery, dQErr = crud.computeSoftDeleteQuery(helper.ComputeWhereQuery(crud.QueryParams, 0))
+	} else {
+		deleteQuery, dQErr = helper.ComputeDeleteQueryByParam(crud.TableName, crud.QueryParams)
+	}
 	if dQErr != nil {
 		return mcresponse.GetResMessage("deleteError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Error computing delete-query: %v", dQErr.Error()),
 			Value:   nil,
 		})
 	}
-	commandTag, delErr := crud.AppDb.Exec(context.Background(), deleteQuery)
+	// referential-integrity actions, of the child-relations
+	actionQueries, aQErr := helper.ComputeDeleteActionQueries(crud.TableName, crud.ChildRelations, deleteQuery.WhereQuery, crud.SoftDeleteField != "")
+	if aQErr != nil {
+		return mcresponse.GetResMessage("deleteError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing relation-actions: %v", aQErr.Error()),
+			Value:   nil,
+		})
+	}
+	return crud.deleteRecords(deleteQuery, actionQueries)
+}
+
+// deleteRecords method performs the relation action-queries (OnDelete) and the delete-query, in a transaction,
+// returning the deleted records, with the ReturnRecord type, and the deleted records count. The delete is
+// rejected (subItems), if the restrict child-relations have related child-records
+func (crud *Crud) deleteRecords(deleteQuery types.DeleteQueryResponseType, actionQueries []types.RelationActionQueryType) mcresponse.ResponseMessage {
+	returningFields, err := crud.ComputeReturningFields()
+	if err == nil && len(returningFields) > 0 {
+		deleteQuery.DeleteQuery, err = helper.ComputeReturningQuery(deleteQuery.DeleteQuery, returningFields)
+	}
+	if err != nil {
+		return mcresponse.GetResMessage("deleteError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing returning-query: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	// perform delete action, via transaction:
+	tx, txErr := crud.AppDb.Begin(context.Background())
+	if txErr != nil {
+		return mcresponse.GetResMessage("deleteError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error deleting record(s): %v", txErr.Error()),
+			Value:   nil,
+		})
+	}
+	defer tx.Rollback(context.Background())
+	// referential-integrity: restrict-checks, cascade, null and default actions
+	for _, actionQuery := range actionQueries {
+		if actionQuery.Action == ormActions.Restrict {
+			var exists bool
+			if err = tx.QueryRow(context.Background(), actionQuery.ActionQuery, deleteQuery.FieldValues...).Scan(&exists); err == nil && exists {
+				_ = tx.Rollback(context.Background())
+				return mcresponse.GetResMessage("subItems", mcresponse.ResponseMessageOptions{
+					Message: fmt.Sprintf("Record(s) include related child-records [relation: %v | table: %v], which must be removed first", actionQuery.RelationName, actionQuery.ChildTable),
+					Value:   nil,
+				})
+			}
+		} else {
+			_, err = tx.Exec(context.Background(), actionQuery.ActionQuery, deleteQuery.FieldValues...)
+		}
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+			return mcresponse.GetResMessage("deleteError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error performing the %v action [relation: %v]: %v", actionQuery.Action, actionQuery.RelationName, err.Error()),
+				Value:   nil,
+			})
+		}
+	}
+	var deleteCount int
+	var returnRecs []interface{}
+	var delErr error
+	if len(returningFields) > 0 {
+		returnRecs, delErr = crud.queryReturningRecords(tx.Query, deleteQuery.DeleteQuery, deleteQuery.FieldValues, returningFields)
+		deleteCount = len(returnRecs)
+	} else {
+		commandTag, execErr := tx.Exec(context.Background(), deleteQuery.DeleteQuery, deleteQuery.FieldValues...)
+		deleteCount, delErr = int(commandTag.RowsAffected()), execErr
+	}
 	if delErr != nil {
+		_ = tx.Rollback(context.Background())
 		return mcresponse.GetResMessage("deleteError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Error deleting record(s): %v", delErr.Error()),
 			Value:   nil,
 		})
 	}
+	// commit
+	if txcErr := tx.Commit(context.Background()); txcErr != nil {
+		_ = tx.Rollback(context.Background())
+		return mcresponse.GetResMessage("deleteError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error deleting record(s): %v", txcErr.Error()),
+			Value:   nil,
+		})
+	}
 
 	// delete cache
 	_ = mccache.DeleteHashCache(crud.TableName, crud.HashKey, "hash")
 
 	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
 		Message: "Record(s) deleted successfully",
-		Value:   commandTag.Delete(),
+		Value: types.CrudResultType{
+			QueryParam:   crud.QueryParams,
+			RecordIds:    crud.RecordIds,
+			RecordCount:  deleteCount,
+			TableRecords: returnRecs,
+		},
 	})
 }
 
-// DeleteAll method deletes or removes all records in the tables. Recommended for admin-users only
-// Use if and only if you know what you are doing
+// DeleteAll method deletes or removes all records in the tables, or soft-deletes, with the SoftDeleteField.
+// Recommended for admin-users only. Use if and only if you know what you are doing
 func (crud *Crud) DeleteAll() mcresponse.ResponseMessage {
 	// ***** perform DELETE-ALL-RECORDS FROM A TABLE, IF RELATIONS/CONSTRAINTS PERMIT *****
 	// ***** && IF-AND-ONLY-IF-YOU-KNOW-WHAT-YOU-ARE-DOING *****
 	// compute delete query
 	delQuery := fmt.Sprintf("DELETE FROM %v", crud.TableName)
+	if crud.SoftDeleteField != "" {
+		softDeleteQuery, err := crud.computeSoftDeleteQuery(types.WhereQueryResponseType{}, nil)
+		if err != nil {
+			return mcresponse.GetResMessage("deleteError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error computing delete-query: %v", err.Error()),
+				Value:   nil,
+			})
+		}
+		delQuery = softDeleteQuery.DeleteQuery
+	}
 	commandTag, delErr := crud.AppDb.Exec(context.Background(), delQuery)
 	if delErr != nil {
 		return mcresponse.GetResMessage("deleteError", mcresponse.ResponseMessageOptions{
@@ -103,20 +203,29 @@ func (crud *Crud) DeleteAll() mcresponse.ResponseMessage {
 
 	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
 		Message: "Record(s) deleted successfully | " + logMessage,
-		Value:   commandTag.Delete(),
+		Value: types.CrudResultType{
+			RecordCount: int(commandTag.RowsAffected()),
+		},
 	})
 }
 
-func (crud *Crud) DeleteByIdLog(tableFields []string, tableFieldPointers []interface{}) mcresponse.ResponseMessage {
-	// get records to delete, for audit-log
-	if crud.LogDelete && len(tableFields) == len(tableFieldPointers) {
-		getRes := crud.GetById(tableFields, tableFieldPointers)
-		value, _ := getRes.Value.(types.CrudResultType)
-		crud.CurrentRecords = value.TableRecords
+func (crud *Crud) DeleteByIdLog(recParam interface{}) mcresponse.ResponseMessage {
+	// return the deleted records (recParam type), for audit-log
+	if crud.LogDelete && crud.ReturnRecord == nil {
+		crud.ReturnRecord = recParam
+		// reset, after the delete-log task
+		defer func() { crud.ReturnRecord = nil }()
 	}
 
 	// perform delete-by-id
 	delRes := crud.DeleteById()
+	// the failed delete (e.g. the restrict-relation subItems) is returned, without the audit-log
+	if delRes.Code != "success" {
+		return delRes
+	}
+	if value, ok := delRes.Value.(types.CrudResultType); ok {
+		crud.CurrentRecords = value.TableRecords
+	}
 
 	// perform audit-log
 	logMessage := ""
@@ -139,16 +248,23 @@ func (crud *Crud) DeleteByIdLog(tableFields []string, tableFieldPointers []inter
 	})
 }
 
-func (crud *Crud) DeleteByParamLog(tableFields []string, tableFieldPointers []interface{}) mcresponse.ResponseMessage {
-	// get records to delete, for audit-log
-	if crud.LogDelete && len(tableFields) == len(tableFieldPointers) {
-		getRes := crud.GetByParam(tableFields, tableFieldPointers)
-		value, _ := getRes.Value.(types.CrudResultType)
-		crud.CurrentRecords = value.TableRecords
+func (crud *Crud) DeleteByParamLog(recParam interface{}) mcresponse.ResponseMessage {
+	// return the deleted records (recParam type), for audit-log
+	if crud.LogDelete && crud.ReturnRecord == nil {
+		crud.ReturnRecord = recParam
+		// reset, after the delete-log task
+		defer func() { crud.ReturnRecord = nil }()
 	}
 
 	// perform delete-by-param
 	delRes := crud.DeleteByParam()
+	// the failed delete (e.g. the restrict-relation subItems) is returned, without the audit-log
+	if delRes.Code != "success" {
+		return delRes
+	}
+	if value, ok := delRes.Value.(types.CrudResultType); ok {
+		crud.CurrentRecords = value.TableRecords
+	}
 
 	// perform audit-log
 	logMessage := ""
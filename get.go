@@ -7,6 +7,7 @@ package mcorm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/abbeymart/mcauditlog"
 	"github.com/abbeymart/mccache"
@@ -14,6 +15,8 @@ import (
 	"github.com/abbeymart/mcorm/types"
 	"github.com/abbeymart/mcorm/types/tasks"
 	"github.com/abbeymart/mcresponse"
+	"reflect"
+	"strings"
 	"time"
 )
 
@@ -21,10 +24,7 @@ import (
 // constrained by optional skip and limit parameters
 func (crud *Crud) GetById(recParam interface{}) mcresponse.ResponseMessage {
 	// validate recParam as a struct type
-	switch recParam.(type) {
-	case struct{}:
-		break
-	default:
+	if !helper.IsStructType(recParam) {
 		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("The recParam type must be a struct{} object"),
 			Value:   nil,
@@ -33,13 +33,29 @@ func (crud *Crud) GetById(recParam interface{}) mcresponse.ResponseMessage {
 	// check cache
 	getCacheRes := mccache.GetHashCache(crud.TableName, crud.HashKey)
 	val, ok := getCacheRes.Value.([]interface{})
-	if getCacheRes.Ok && ok && len(val) > 0 {
+	if getCacheRes.Ok && ok && len(val) > 0 && !crud.CursorPaging {
+		// total records count, for paginated queries
+		whereRes, err := crud.activeWhere(helper.ComputeWhereQueryByIds(crud.RecordIds, 0))
+		if err != nil {
+			return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error computing where-query: %v", err.Error()),
+				Value:   nil,
+			})
+		}
+		totalCount, err := crud.ComputeTotalCount(whereRes)
+		if err != nil {
+			return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error computing the total records count: %v", err.Error()),
+				Value:   nil,
+			})
+		}
 		return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
 			Message: "records successfully retrieved from the cache",
 			Value: types.CrudResultType{
 				QueryParam:   crud.QueryParams,
 				RecordIds:    crud.RecordIds,
 				RecordCount:  len(val),
+				TotalCount:   totalCount,
 				TableRecords: val,
 			},
 		})
@@ -52,22 +68,31 @@ func (crud *Crud) GetById(recParam interface{}) mcresponse.ResponseMessage {
 			Value:   nil,
 		})
 	}
-	getQuery, err := helper.ComputeSelectQueryById(crud.TableName, crud.RecordIds, tableFields)
+	// projected tableFields, from projectParams
+	getFields, err := crud.ComputeGetFields(tableFields)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing projection-fields: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	selectQuery, err := crud.activeSelect(helper.ComputeSelectQueryById(crud.TableName, crud.RecordIds, getFields))
 	if err != nil {
 		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Error computing select/read-query: %v", err.Error()),
-			Value:   getQuery,
+			Value:   nil,
 		})
 	}
-	// include options: limit... TODO: sort?
-	if crud.Skip > 0 {
-		getQuery += fmt.Sprintf(" SKIP %v", crud.Skip)
-	}
-	if crud.Limit > 0 {
-		getQuery += fmt.Sprintf(" LIMIT %v", crud.Limit)
+	// include options: keyset-cursor, sort, skip & limit
+	getQuery, fieldValues, sortItems, err := crud.ComputeGetQuery(selectQuery)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing paging/sort-query: %v", err.Error()),
+			Value:   nil,
+		})
 	}
 	// perform crud-task action
-	rows, qRowErr := crud.AppDb.Query(context.Background(), getQuery)
+	rows, qRowErr := crud.AppDb.Query(context.Background(), getQuery, fieldValues...)
 	if qRowErr != nil {
 		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Db query Error: %v", qRowErr.Error()),
@@ -78,10 +103,19 @@ func (crud *Crud) GetById(recParam interface{}) mcresponse.ResponseMessage {
 	// check rows count
 	var rowCount = 0
 	var getResults []interface{}
+	var lastFieldPointers []interface{}
 	//var getResult = map[string]interface{}{}
 	// TODO: compute jsonFields from model-struct{}
 	for rows.Next() {
-		rowScanErr := rows.Scan(&recParam)
+		// scan the projected fields into a new record, the excluded fields remain zero-valued
+		getRec, fieldPointers, scanErr := helper.StructScanFields(recParam, "mcorm", getFields)
+		if scanErr != nil {
+			return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error reading/getting records[row-scan]: %v", scanErr.Error()),
+				Value:   nil,
+			})
+		}
+		rowScanErr := rows.Scan(fieldPointers...)
 		if rowScanErr != nil {
 			return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
 				Message: fmt.Sprintf("Error reading/getting records[row-scan]: %v", rowScanErr.Error()),
@@ -89,7 +123,7 @@ func (crud *Crud) GetById(recParam interface{}) mcresponse.ResponseMessage {
 			})
 		}
 		// get snapshot value (clone) from the pointer | transform value to json-value-format
-		jByte, jErr := json.Marshal(recParam)
+		jByte, jErr := json.Marshal(getRec)
 		if jErr != nil {
 			return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
 				Message: fmt.Sprintf("Error transforming result-value into json-value-format: %v", jErr.Error()),
@@ -105,6 +139,7 @@ func (crud *Crud) GetById(recParam interface{}) mcresponse.ResponseMessage {
 			})
 		}
 		getResults = append(getResults, gValue)
+		lastFieldPointers = fieldPointers
 		rowCount += 1
 
 	}
@@ -115,6 +150,25 @@ func (crud *Crud) GetById(recParam interface{}) mcresponse.ResponseMessage {
 			Value:   nil,
 		})
 	}
+	// next-cursor, for keyset (cursor) pagination
+	nextCursor, err := crud.ComputeNextCursor(sortItems, getFields, lastFieldPointers, rowCount)
+	if err != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing the next-cursor: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	// total records count, for paginated queries
+	totalCount, err := crud.ComputeTotalCount(types.WhereQueryResponseType{
+		WhereQuery:  selectQuery.WhereQuery,
+		FieldValues: selectQuery.FieldValues,
+	})
+	if err != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing the total records count: %v", err.Error()),
+			Value:   nil,
+		})
+	}
 	// update cache
 	_ = mccache.SetHashCache(crud.TableName, crud.HashKey, getResults, uint(crud.CacheExpire))
 
@@ -138,7 +192,9 @@ func (crud *Crud) GetById(recParam interface{}) mcresponse.ResponseMessage {
 			QueryParam:   crud.QueryParams,
 			RecordIds:    crud.RecordIds,
 			RecordCount:  rowCount,
+			TotalCount:   totalCount,
 			TableRecords: getResults,
+			NextCursor:   nextCursor,
 		},
 	})
 }
@@ -147,13 +203,29 @@ func (crud *Crud) GetById2(rec interface{}, tableFieldPointers []interface{}) mc
 	// check cache
 	getCacheRes := mccache.GetHashCache(crud.TableName, crud.HashKey)
 	val, ok := getCacheRes.Value.([]interface{})
-	if getCacheRes.Ok && ok && len(val) > 0 {
+	if getCacheRes.Ok && ok && len(val) > 0 && !crud.CursorPaging {
+		// total records count, for paginated queries
+		whereRes, err := crud.activeWhere(helper.ComputeWhereQueryByIds(crud.RecordIds, 0))
+		if err != nil {
+			return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error computing where-query: %v", err.Error()),
+				Value:   nil,
+			})
+		}
+		totalCount, err := crud.ComputeTotalCount(whereRes)
+		if err != nil {
+			return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error computing the total records count: %v", err.Error()),
+				Value:   nil,
+			})
+		}
 		return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
 			Message: "records successfully retrieved from the cache",
 			Value: types.CrudResultType{
 				QueryParam:   crud.QueryParams,
 				RecordIds:    crud.RecordIds,
 				RecordCount:  len(val),
+				TotalCount:   totalCount,
 				TableRecords: val,
 			},
 		})
@@ -174,22 +246,37 @@ func (crud *Crud) GetById2(rec interface{}, tableFieldPointers []interface{}) mc
 			Value:   nil,
 		})
 	}
-	getQuery, err := helper.ComputeSelectQueryById(crud.TableName, crud.RecordIds, tableFields)
+	// projected tableFields and tableFieldPointers, from projectParams
+	getFields, err := crud.ComputeGetFields(tableFields)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing projection-fields: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	var getFieldPointers []interface{}
+	for i, fieldName := range tableFields {
+		if helper.ArrayStringContains(getFields, fieldName) {
+			getFieldPointers = append(getFieldPointers, tableFieldPointers[i])
+		}
+	}
+	selectQuery, err := crud.activeSelect(helper.ComputeSelectQueryById(crud.TableName, crud.RecordIds, getFields))
 	if err != nil {
 		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Error computing select/read-query: %v", err.Error()),
-			Value:   getQuery,
+			Value:   nil,
 		})
 	}
-	// include options: limit... TODO: sort?
-	if crud.Skip > 0 {
-		getQuery += fmt.Sprintf(" SKIP %v", crud.Skip)
-	}
-	if crud.Limit > 0 {
-		getQuery += fmt.Sprintf(" LIMIT %v", crud.Limit)
+	// include options: keyset-cursor, sort, skip & limit
+	getQuery, fieldValues, sortItems, err := crud.ComputeGetQuery(selectQuery)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing paging/sort-query: %v", err.Error()),
+			Value:   nil,
+		})
 	}
 	// perform crud-task action
-	rows, qRowErr := crud.AppDb.Query(context.Background(), getQuery)
+	rows, qRowErr := crud.AppDb.Query(context.Background(), getQuery, fieldValues...)
 	if qRowErr != nil {
 		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Db query Error: %v", qRowErr.Error()),
@@ -200,7 +287,7 @@ func (crud *Crud) GetById2(rec interface{}, tableFieldPointers []interface{}) mc
 	// check rows count
 	var rowCount = 0
 	var getResults []interface{}
-	var getResult = map[string]interface{}{}
+	var lastFieldPointers []interface{}
 	// TODO: compute jsonFields from model-struct{}
 	jsonFields, _, err := helper.StructToFieldValues(rec, "json")
 	if err != nil {
@@ -210,14 +297,25 @@ func (crud *Crud) GetById2(rec interface{}, tableFieldPointers []interface{}) mc
 		})
 	}
 	for rows.Next() {
-		if rowScanErr := rows.Scan(tableFieldPointers...); rowScanErr != nil {
+		// reset the field-pointers excluded by the projection (not scanned), from the previous row
+		getResult := map[string]interface{}{}
+		for i, fieldPointer := range tableFieldPointers {
+			if fieldValue := reflect.ValueOf(fieldPointer); !helper.ArrayStringContains(getFields, tableFields[i]) &&
+				fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+				fieldValue.Elem().Set(reflect.Zero(fieldValue.Elem().Type()))
+			}
+		}
+		if rowScanErr := rows.Scan(getFieldPointers...); rowScanErr != nil {
 			return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
 				Message: fmt.Sprintf("Error reading/getting records[row-scan]: %v", rowScanErr.Error()),
 				Value:   nil,
 			})
 		} else {
-			// extract values from tableFieldPointers
+			// extract values from tableFieldPointers, of the projected fields
 			for i, fieldPointer := range tableFieldPointers {
+				if !helper.ArrayStringContains(getFields, tableFields[i]) {
+					continue
+				}
 				switch fieldPointer.(type) {
 				case *time.Time:
 					val := fieldPointer.(*time.Time)
@@ -260,6 +358,7 @@ func (crud *Crud) GetById2(rec interface{}, tableFieldPointers []interface{}) mc
 				})
 			}
 			getResults = append(getResults, gValue)
+			lastFieldPointers = getFieldPointers
 			rowCount += 1
 		}
 	}
@@ -270,6 +369,25 @@ func (crud *Crud) GetById2(rec interface{}, tableFieldPointers []interface{}) mc
 			Value:   nil,
 		})
 	}
+	// next-cursor, for keyset (cursor) pagination
+	nextCursor, err := crud.ComputeNextCursor(sortItems, getFields, lastFieldPointers, rowCount)
+	if err != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing the next-cursor: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	// total records count, for paginated queries
+	totalCount, err := crud.ComputeTotalCount(types.WhereQueryResponseType{
+		WhereQuery:  selectQuery.WhereQuery,
+		FieldValues: selectQuery.FieldValues,
+	})
+	if err != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing the total records count: %v", err.Error()),
+			Value:   nil,
+		})
+	}
 	// update cache
 	_ = mccache.SetHashCache(crud.TableName, crud.HashKey, getResults, uint(crud.CacheExpire))
 
@@ -293,7 +411,9 @@ func (crud *Crud) GetById2(rec interface{}, tableFieldPointers []interface{}) mc
 			QueryParam:   crud.QueryParams,
 			RecordIds:    crud.RecordIds,
 			RecordCount:  rowCount,
+			TotalCount:   totalCount,
 			TableRecords: getResults,
+			NextCursor:   nextCursor,
 		},
 	})
 }
@@ -302,10 +422,7 @@ func (crud *Crud) GetById2(rec interface{}, tableFieldPointers []interface{}) mc
 // constrained by optional skip and limit parameters
 func (crud *Crud) GetByParam(recParam interface{}) mcresponse.ResponseMessage {
 	// validate recParam as a struct type
-	switch recParam.(type) {
-	case struct{}:
-		break
-	default:
+	if !helper.IsStructType(recParam) {
 		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("The recParam type must be a struct{} object"),
 			Value:   nil,
@@ -314,13 +431,29 @@ func (crud *Crud) GetByParam(recParam interface{}) mcresponse.ResponseMessage {
 	// check cache
 	getCacheRes := mccache.GetHashCache(crud.TableName, crud.HashKey)
 	val, ok := getCacheRes.Value.([]interface{})
-	if getCacheRes.Ok && ok && len(val) > 0 {
+	if getCacheRes.Ok && ok && len(val) > 0 && !crud.CursorPaging {
+		// total records count, for paginated queries
+		whereRes, err := crud.activeWhere(helper.ComputeWhereQuery(crud.QueryParams, 0))
+		if err != nil {
+			return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error computing where-query: %v", err.Error()),
+				Value:   nil,
+			})
+		}
+		totalCount, err := crud.ComputeTotalCount(whereRes)
+		if err != nil {
+			return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error computing the total records count: %v", err.Error()),
+				Value:   nil,
+			})
+		}
 		return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
 			Message: "records successfully retrieved from the cache",
 			Value: types.CrudResultType{
 				QueryParam:   crud.QueryParams,
 				RecordIds:    crud.RecordIds,
 				RecordCount:  len(val),
+				TotalCount:   totalCount,
 				TableRecords: val,
 			},
 		})
@@ -333,21 +466,33 @@ func (crud *Crud) GetByParam(recParam interface{}) mcresponse.ResponseMessage {
 			Value:   nil,
 		})
 	}
+	// projected tableFields, from projectParams
+	getFields, err := crud.ComputeGetFields(tableFields)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing projection-fields: %v", err.Error()),
+			Value:   nil,
+		})
+	}
 	logMessage := ""
-	getQuery, err := helper.ComputeSelectQueryByParam(crud.TableName, crud.QueryParams, tableFields)
+	selectQuery, err := crud.activeSelect(helper.ComputeSelectQueryByParam(crud.TableName, crud.QueryParams, getFields))
 	if err != nil {
 		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Error computing select/read-query: %v", err.Error()),
-			Value:   getQuery,
+			Value:   nil,
 		})
 	}
-	// include options: limit TODO: sort?
-	if crud.Limit > 0 {
-		getQuery += fmt.Sprintf(" LIMIT %v", crud.Limit)
+	// include options: keyset-cursor, sort, skip & limit
+	getQuery, fieldValues, sortItems, err := crud.ComputeGetQuery(selectQuery)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing paging/sort-query: %v", err.Error()),
+			Value:   nil,
+		})
 	}
 	// perform crud-task action
 	//fmt.Printf("getQuery-param: %v\n", getQuery)
-	rows, qRowErr := crud.AppDb.Query(context.Background(), getQuery)
+	rows, qRowErr := crud.AppDb.Query(context.Background(), getQuery, fieldValues...)
 	if qRowErr != nil {
 		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Db query Error: %v", qRowErr.Error()),
@@ -358,9 +503,18 @@ func (crud *Crud) GetByParam(recParam interface{}) mcresponse.ResponseMessage {
 	// check rows count
 	var rowCount = 0
 	var getResults []interface{}
+	var lastFieldPointers []interface{}
 	//var getResult = map[string]interface{}{}
 	for rows.Next() {
-		rowScanErr := rows.Scan(&recParam)
+		// scan the projected fields into a new record, the excluded fields remain zero-valued
+		getRec, fieldPointers, scanErr := helper.StructScanFields(recParam, "mcorm", getFields)
+		if scanErr != nil {
+			return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error reading/getting records[row-scan]: %v", scanErr.Error()),
+				Value:   nil,
+			})
+		}
+		rowScanErr := rows.Scan(fieldPointers...)
 		if rowScanErr != nil {
 			return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
 				Message: fmt.Sprintf("Error reading/getting records[row-scan]: %v", rowScanErr.Error()),
@@ -368,7 +522,7 @@ func (crud *Crud) GetByParam(recParam interface{}) mcresponse.ResponseMessage {
 			})
 		}
 		// get snapshot value (clone) from the pointer | transform value to json-value-format
-		jByte, jErr := json.Marshal(recParam)
+		jByte, jErr := json.Marshal(getRec)
 		if jErr != nil {
 			return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
 				Message: fmt.Sprintf("Error transforming result-value into json-value-format: %v", jErr.Error()),
@@ -384,6 +538,7 @@ func (crud *Crud) GetByParam(recParam interface{}) mcresponse.ResponseMessage {
 			})
 		}
 		getResults = append(getResults, gValue)
+		lastFieldPointers = fieldPointers
 		rowCount += 1
 	}
 
@@ -399,6 +554,25 @@ func (crud *Crud) GetByParam(recParam interface{}) mcresponse.ResponseMessage {
 		})
 	}
 
+	// next-cursor, for keyset (cursor) pagination
+	nextCursor, err := crud.ComputeNextCursor(sortItems, getFields, lastFieldPointers, rowCount)
+	if err != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing the next-cursor: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	// total records count, for paginated queries
+	totalCount, err := crud.ComputeTotalCount(types.WhereQueryResponseType{
+		WhereQuery:  selectQuery.WhereQuery,
+		FieldValues: selectQuery.FieldValues,
+	})
+	if err != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing the total records count: %v", err.Error()),
+			Value:   nil,
+		})
+	}
 	// update cache
 	_ = mccache.SetHashCache(crud.TableName, crud.HashKey, getResults, uint(crud.CacheExpire))
 
@@ -421,7 +595,9 @@ func (crud *Crud) GetByParam(recParam interface{}) mcresponse.ResponseMessage {
 			QueryParam:   crud.QueryParams,
 			RecordIds:    crud.RecordIds,
 			RecordCount:  rowCount,
+			TotalCount:   totalCount,
 			TableRecords: getResults,
+			NextCursor:   nextCursor,
 		},
 	})
 }
@@ -429,10 +605,7 @@ func (crud *Crud) GetByParam(recParam interface{}) mcresponse.ResponseMessage {
 // GetAll method fetches/gets/reads all record(s), constrained by optional skip and limit parameters
 func (crud *Crud) GetAll(recParam interface{}) mcresponse.ResponseMessage {
 	// validate recParam as a struct type
-	switch recParam.(type) {
-	case struct{}:
-		break
-	default:
+	if !helper.IsStructType(recParam) {
 		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("The recParam type must be a struct{} object"),
 			Value:   nil,
@@ -446,23 +619,40 @@ func (crud *Crud) GetAll(recParam interface{}) mcresponse.ResponseMessage {
 			Value:   nil,
 		})
 	}
+	// projected tableFields, from projectParams
+	getFields, err := crud.ComputeGetFields(tableFields)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing projection-fields: %v", err.Error()),
+			Value:   nil,
+		})
+	}
 	logMessage := ""
-	getQuery, err := helper.ComputeSelectQueryAll(crud.TableName, tableFields)
+	selectAllQuery, err := helper.ComputeSelectQueryAll(crud.TableName, getFields)
 	if err != nil {
 		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Error computing select/read-query: %v", err.Error()),
-			Value:   getQuery,
+			Value:   nil,
 		})
 	}
-	// include options: skip && limit TODO: sort?
-	if crud.Limit > 0 {
-		getQuery += fmt.Sprintf(" LIMIT %v", crud.Limit)
+	// exclude the soft-deleted records
+	selectQuery, err := crud.activeSelect(types.SelectQueryResponseType{SelectQuery: selectAllQuery}, nil)
+	if err != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing select/read-query: %v", err.Error()),
+			Value:   nil,
+		})
 	}
-	if crud.Skip > 0 {
-		getQuery += fmt.Sprintf(" OFFSET %v", crud.Skip)
+	// include options: keyset-cursor, sort, skip & limit
+	getQuery, fieldValues, sortItems, err := crud.ComputeGetQuery(selectQuery)
+	if err != nil {
+		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing paging/sort-query: %v", err.Error()),
+			Value:   nil,
+		})
 	}
 	// perform crud-task action
-	rows, qRowErr := crud.AppDb.Query(context.Background(), getQuery)
+	rows, qRowErr := crud.AppDb.Query(context.Background(), getQuery, fieldValues...)
 	if qRowErr != nil {
 		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Db query Error: %v", qRowErr.Error()),
@@ -473,9 +663,18 @@ func (crud *Crud) GetAll(recParam interface{}) mcresponse.ResponseMessage {
 	// check rows count
 	var rowCount = 0
 	var getResults []interface{}
+	var lastFieldPointers []interface{}
 	//getResult := map[string]interface{}{}
 	for rows.Next() {
-		rowScanErr := rows.Scan(&recParam)
+		// scan the projected fields into a new record, the excluded fields remain zero-valued
+		getRec, fieldPointers, scanErr := helper.StructScanFields(recParam, "mcorm", getFields)
+		if scanErr != nil {
+			return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error reading/getting records[row-scan]: %v", scanErr.Error()),
+				Value:   nil,
+			})
+		}
+		rowScanErr := rows.Scan(fieldPointers...)
 		if rowScanErr != nil {
 			return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
 				Message: fmt.Sprintf("Error reading/getting records[row-scan]: %v", rowScanErr.Error()),
@@ -483,7 +682,7 @@ func (crud *Crud) GetAll(recParam interface{}) mcresponse.ResponseMessage {
 			})
 		}
 		// get snapshot value (clone) from the pointer | transform value to json-value-format
-		jByte, jErr := json.Marshal(recParam)
+		jByte, jErr := json.Marshal(getRec)
 		if jErr != nil {
 			return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
 				Message: fmt.Sprintf("Error transforming result-value into json-value-format: %v", jErr.Error()),
@@ -499,6 +698,7 @@ func (crud *Crud) GetAll(recParam interface{}) mcresponse.ResponseMessage {
 			})
 		}
 		getResults = append(getResults, gValue)
+		lastFieldPointers = fieldPointers
 		rowCount += 1
 
 	}
@@ -510,6 +710,26 @@ func (crud *Crud) GetAll(recParam interface{}) mcresponse.ResponseMessage {
 		})
 	}
 
+	// next-cursor, for keyset (cursor) pagination
+	nextCursor, err := crud.ComputeNextCursor(sortItems, getFields, lastFieldPointers, rowCount)
+	if err != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing the next-cursor: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	// total records count, for paginated queries
+	totalCount, err := crud.ComputeTotalCount(types.WhereQueryResponseType{
+		WhereQuery:  selectQuery.WhereQuery,
+		FieldValues: selectQuery.FieldValues,
+	})
+	if err != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing the total records count: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+
 	// perform audit-log
 	if crud.LogRead {
 		auditInfo := mcauditlog.PgxAuditLogOptionsType{
@@ -529,7 +749,101 @@ func (crud *Crud) GetAll(recParam interface{}) mcresponse.ResponseMessage {
 			QueryParam:   crud.QueryParams,
 			RecordIds:    crud.RecordIds,
 			RecordCount:  rowCount,
+			TotalCount:   totalCount,
 			TableRecords: getResults,
+			NextCursor:   nextCursor,
 		},
 	})
 }
+
+// ComputeGetFields method computes the projected table-fields, from the projectParams, including the sort-fields
+// for keyset (cursor) pagination
+func (crud *Crud) ComputeGetFields(tableFields []string) ([]string, error) {
+	getFields, err := helper.ComputeGetFields(tableFields, crud.ProjectParams)
+	if err != nil || !crud.CursorPaging {
+		return getFields, err
+	}
+	// include the sort-fields, in the order of the tableFields, to compute the next-cursor
+	var sortFields []string
+	for _, item := range helper.ComputeSortItems(crud.SortParams) {
+		if !helper.ArrayStringContains(tableFields, item.FieldName) {
+			return nil, errors.New(fmt.Sprintf("sort-field [%v] is required in the record-fields for cursor pagination", item.FieldName))
+		}
+		sortFields = append(sortFields, item.FieldName)
+	}
+	var cursorFields []string
+	for _, fieldName := range tableFields {
+		if helper.ArrayStringContains(getFields, fieldName) || helper.ArrayStringContains(sortFields, fieldName) {
+			cursorFields = append(cursorFields, fieldName)
+		}
+	}
+	return cursorFields, nil
+}
+
+// ComputeGetQuery method composes the get-query from the select-query, including the keyset-cursor condition,
+// order-by (sort) and limit/offset scripts, and returns the query, the placeholder-values and the sort-items,
+// for the next-cursor computation (cursor pagination only)
+func (crud *Crud) ComputeGetQuery(selectQuery types.SelectQueryResponseType) (string, []interface{}, types.SortParamType, error) {
+	getQuery := selectQuery.SelectQuery
+	fieldValues := selectQuery.FieldValues
+	sortParams := crud.SortParams
+	var sortItems types.SortParamType
+	if crud.CursorPaging {
+		// keyset pagination requires deterministic ordering, by the sort-params and id
+		sortItems = helper.ComputeSortItems(sortParams)
+		sortParams = sortItems
+		if crud.Cursor != "" {
+			cursorRes, err := helper.ComputeCursorQuery(sortItems, crud.Cursor, len(fieldValues))
+			if err != nil {
+				return "", nil, nil, err
+			}
+			if selectQuery.WhereQuery != "" {
+				// combine the where-conditions and the cursor-condition
+				getQuery = strings.TrimSuffix(getQuery, selectQuery.WhereQuery) +
+					fmt.Sprintf("WHERE (%v) AND %v", strings.TrimPrefix(selectQuery.WhereQuery, "WHERE "), cursorRes.WhereQuery)
+			} else {
+				getQuery += " WHERE " + cursorRes.WhereQuery
+			}
+			fieldValues = append(fieldValues, cursorRes.FieldValues...)
+		}
+	}
+	sortQuery, err := helper.ComputeSortQuery(sortParams, crud.RecordDesc)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if sortQuery != "" {
+		getQuery += " " + sortQuery
+	}
+	if crud.Limit > 0 {
+		getQuery += fmt.Sprintf(" LIMIT %v", crud.Limit)
+	}
+	// offset is replaced by the cursor-condition, for cursor pagination
+	if crud.Skip > 0 && !crud.CursorPaging {
+		getQuery += fmt.Sprintf(" OFFSET %v", crud.Skip)
+	}
+	return getQuery, fieldValues, sortItems, nil
+}
+
+// ComputeNextCursor method computes the next-cursor from the last record's sort-field-values (lastFieldPointers,
+// in the order of the getFields), for a complete page (rowCount == limit) of the keyset (cursor) pagination.
+// Returns an empty cursor for the last page or if cursor pagination is not requested
+func (crud *Crud) ComputeNextCursor(sortItems types.SortParamType, getFields []string, lastFieldPointers []interface{}, rowCount int) (string, error) {
+	if !crud.CursorPaging || crud.Limit < 1 || rowCount < crud.Limit || len(lastFieldPointers) != len(getFields) {
+		return "", nil
+	}
+	var sortValues []interface{}
+	for _, item := range sortItems {
+		fieldIndex := -1
+		for i, fieldName := range getFields {
+			if fieldName == item.FieldName {
+				fieldIndex = i
+				break
+			}
+		}
+		if fieldIndex < 0 {
+			return "", errors.New(fmt.Sprintf("sort-field [%v] value is required to compute the next-cursor", item.FieldName))
+		}
+		sortValues = append(sortValues, reflect.Indirect(reflect.ValueOf(lastFieldPointers[fieldIndex])).Interface())
+	}
+	return helper.ComputeNextCursor(sortItems, sortValues)
+}
@@ -47,8 +47,7 @@ func TestGet(t *testing.T) {
 
 	// check db-connection-error
 	if err != nil {
-		fmt.Printf("*****db-connection-error: %v\n", err.Error())
-		return
+		t.Skipf("*****db-connection-error: %v", err.Error())
 	}
 
 	auditModel := types.ModelType{
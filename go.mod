@@ -11,5 +11,6 @@ require (
 	github.com/abbeymart/mctypes v0.4.4
 	github.com/abbeymart/mcutils v0.1.5 // indirect
 	github.com/asaskevich/govalidator v0.0.0-20210307081110-f21760c49a8d
+	github.com/jackc/pgtype v1.7.0
 	github.com/jackc/pgx/v4 v4.11.0
 )
@@ -0,0 +1,88 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-13 | @Updated: 2021-01-13
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute aggregate (group-by)-SQL script
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/aggregateOperators"
+	"strings"
+)
+
+// ComputeAggregateQuery compose the aggregate SELECT query, grouped by the group-fields, with optional where-query
+// (by record-ids or where-params) and having-params. The records are ordered by the group-fields. The camelCase
+// group/aggregate field-names are converted to the snake_case column-names
+func ComputeAggregateQuery(tableName string, aggregate types.AggregateParamType, whereRes types.WhereQueryResponseType, recordDesc types.RecordDescType) (types.SelectQueryResponseType, error) {
+	if tableName == "" || len(aggregate.Aggregates) < 1 {
+		return types.SelectQueryResponseType{}, errors.New("table-name and aggregates are required to perform the aggregate operation")
+	}
+	// group-fields and aggregate-expressions, by field-name/alias, for the select and having scripts
+	var selectFields, groupFields []string
+	fieldExprs := map[string]string{}
+	for _, groupField := range aggregate.GroupFields {
+		if !IsModelField(groupField, recordDesc) {
+			return types.SelectQueryResponseType{}, errors.New(fmt.Sprintf("Invalid or unknown group field-name: %v", groupField))
+		}
+		columnName := ToSnakeCase(groupField)
+		selectFields = append(selectFields, columnName)
+		groupFields = append(groupFields, columnName)
+		fieldExprs[groupField] = columnName
+		fieldExprs[columnName] = columnName
+	}
+	for _, item := range aggregate.Aggregates {
+		operator := strings.ToLower(item.Operator)
+		switch operator {
+		case aggregateOperators.Count, aggregateOperators.Sum, aggregateOperators.Avg, aggregateOperators.Min, aggregateOperators.Max:
+			break
+		default:
+			return types.SelectQueryResponseType{}, errors.New(fmt.Sprintf("Unknown or unsupported aggregate operator: %v", item.Operator))
+		}
+		fieldName := ToSnakeCase(item.FieldName)
+		alias := item.Alias
+		if fieldName == "*" && operator == aggregateOperators.Count {
+			if alias == "" {
+				alias = operator + "_all"
+			}
+		} else if !IsModelField(item.FieldName, recordDesc) {
+			return types.SelectQueryResponseType{}, errors.New(fmt.Sprintf("Invalid or unknown aggregate field-name: %v", item.FieldName))
+		} else if alias == "" {
+			alias = operator + "_" + fieldName
+		}
+		if !IsFieldName(alias) {
+			return types.SelectQueryResponseType{}, errors.New(fmt.Sprintf("Invalid aggregate alias: %v", alias))
+		}
+		if _, ok := fieldExprs[alias]; ok {
+			return types.SelectQueryResponseType{}, errors.New(fmt.Sprintf("Duplicate aggregate alias or group field-name: %v", alias))
+		}
+		fieldExpr := fmt.Sprintf("%v(%v)", strings.ToUpper(operator), fieldName)
+		selectFields = append(selectFields, fmt.Sprintf("%v AS %v", fieldExpr, alias))
+		fieldExprs[alias] = fieldExpr
+	}
+	aggregateQuery := fmt.Sprintf("SELECT %v FROM %v", strings.Join(selectFields, ", "), tableName)
+	fieldValues := whereRes.FieldValues
+	if whereRes.WhereQuery != "" {
+		aggregateQuery += " " + whereRes.WhereQuery
+	}
+	if len(groupFields) > 0 {
+		aggregateQuery += " GROUP BY " + strings.Join(groupFields, ", ")
+	}
+	if len(aggregate.HavingParams) > 0 {
+		havingRes, err := ComputeHavingQuery(aggregate.HavingParams, len(fieldValues), fieldExprs)
+		if err != nil {
+			return types.SelectQueryResponseType{}, errors.New(fmt.Sprintf("error computing having-query condition(s): %v", err.Error()))
+		}
+		aggregateQuery += " " + havingRes.WhereQuery
+		fieldValues = append(append([]interface{}{}, fieldValues...), havingRes.FieldValues...)
+	}
+	if len(groupFields) > 0 {
+		aggregateQuery += " ORDER BY " + strings.Join(groupFields, ", ")
+	}
+	return types.SelectQueryResponseType{
+		SelectQuery: aggregateQuery,
+		WhereQuery:  whereRes.WhereQuery,
+		FieldValues: fieldValues,
+	}, nil
+}
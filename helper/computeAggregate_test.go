@@ -0,0 +1,84 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-13 | @Updated: 2021-01-13
+// @Company: mConnect.biz | @License: MIT
+// @Description: aggregate-query test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+var aggregateRecordDesc = types.RecordDescType{
+	"category": {
+		FieldType: datatypes.String,
+	},
+	"amount": {
+		FieldType: datatypes.Float,
+	},
+	"unitPrice": {
+		FieldType: datatypes.Float,
+	},
+}
+
+func TestComputeAggregateQuery(t *testing.T) {
+	aggregateParams := types.AggregateParamType{
+		Aggregates: []types.AggregateItemType{
+			{FieldName: "*", Operator: "count"},
+			{FieldName: "amount", Operator: "sum"},
+			{FieldName: "amount", Operator: "avg", Alias: "average"},
+		},
+		GroupFields: []string{"category"},
+		HavingParams: types.QueryParamType{
+			{
+				GroupName:  "having",
+				GroupOrder: 1,
+				GroupItems: []types.QueryItemType{
+					{GroupItem: map[string]map[string]interface{}{"sum_amount": {"gt": 100}}, GroupItemOrder: 1},
+				},
+			},
+		},
+	}
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute aggregate-query, with where, group-by and having conditions:",
+		TestFunc: func() {
+			whereRes, _ := ComputeWhereQueryByIds([]string{"10", "20"}, 0)
+			res, err := ComputeAggregateQuery("orders", aggregateParams, whereRes, aggregateRecordDesc)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.SelectQuery, "SELECT category, COUNT(*) AS count_all, SUM(amount) AS sum_amount, AVG(amount) AS average FROM orders WHERE id = ANY($1) GROUP BY category HAVING (SUM(amount)>$2) ORDER BY category", "aggregate-query should match the aggregate-params")
+			mctest.AssertEquals(t, len(res.FieldValues), 2, "field-values count should be: 2")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute aggregate-query with the snake_case column-names, for the camelCase field-names:",
+		TestFunc: func() {
+			res, err := ComputeAggregateQuery("orders", types.AggregateParamType{
+				GroupFields: []string{"createdBy"},
+				Aggregates:  []types.AggregateItemType{{FieldName: "unitPrice", Operator: "max"}},
+			}, types.WhereQueryResponseType{}, aggregateRecordDesc)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.SelectQuery, "SELECT created_by, MAX(unit_price) AS max_unit_price FROM orders GROUP BY created_by ORDER BY created_by", "aggregate-query should match the aggregate-params")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return an error for unknown operator, field-name or having field-name:",
+		TestFunc: func() {
+			_, err := ComputeAggregateQuery("orders", types.AggregateParamType{Aggregates: []types.AggregateItemType{{FieldName: "amount", Operator: "median"}}}, types.WhereQueryResponseType{}, aggregateRecordDesc)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			_, err = ComputeAggregateQuery("orders", types.AggregateParamType{Aggregates: []types.AggregateItemType{{FieldName: "price", Operator: "sum"}}}, types.WhereQueryResponseType{}, aggregateRecordDesc)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			invalidHaving := aggregateParams
+			invalidHaving.HavingParams = types.QueryParamType{
+				{GroupName: "having", GroupOrder: 1, GroupItems: []types.QueryItemType{
+					{GroupItem: map[string]map[string]interface{}{"amount": {"gt": 100}}, GroupItemOrder: 1},
+				}},
+			}
+			_, err = ComputeAggregateQuery("orders", invalidHaving, types.WhereQueryResponseType{}, aggregateRecordDesc)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+
+	mctest.PostTestResult()
+}
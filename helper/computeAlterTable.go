@@ -1,22 +1,321 @@
-// @Author: abbeymart | Abi Akindele | @Created: 2020-12-08 | @Updated: 2020-12-08
+// @Author: abbeymart | Abi Akindele | @Created: 2020-12-08 | @Updated: 2021-01-15
 // @Company: mConnect.biz | @License: MIT
 // @Description: compute alter-table script
 
 package helper
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/abbeymart/mcorm/types"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"regexp"
+	"sort"
+	"strings"
 )
 
-func CreateAlterTableQuery(model types.ModelType) (string, error)  {
+// TableColumnType describes the live table column, from the information_schema
+type TableColumnType struct {
+	ColumnName       string
+	UdtName          string // e.g. varchar, int4, timestamptz, _text
+	MaxLength        int    // character maximum length, for varchar
+	IsNullable       bool
+	ColumnDefault    string
+	UniqueConstraint string // single-column unique constraint-name
+}
+
+// column-types to information_schema udt-names
+var fieldUdtNames = map[string]string{
+	"TEXT":               "text",
+	"UUID":               "uuid",
+	"INTEGER":            "int4",
+	"BIGINT":             "int8",
+	"NUMERIC":            "numeric",
+	"REAL":               "float4",
+	"DOUBLE PRECISION":   "float8",
+	"BOOLEAN":            "bool",
+	"TIMESTAMPTZ":        "timestamptz",
+	"TIMESTAMP":          "timestamp",
+	"DATE":               "date",
+	"TIME":               "time",
+	"INET":               "inet",
+	"TEXT[]":             "_text",
+	"DOUBLE PRECISION[]": "_float8",
+	"BOOLEAN[]":          "_bool",
+	"JSONB":              "jsonb",
+}
 
+// fill-values of the added NOT NULL columns, without a default-value, for the existing records
+var fieldFillValues = map[string]string{
+	"TEXT":               "''",
+	"UUID":               "'00000000-0000-0000-0000-000000000000'",
+	"INTEGER":            "0",
+	"BIGINT":             "0",
+	"NUMERIC":            "0",
+	"REAL":               "0",
+	"DOUBLE PRECISION":   "0",
+	"BOOLEAN":            "FALSE",
+	"TIMESTAMPTZ":        "CURRENT_TIMESTAMP",
+	"TIMESTAMP":          "CURRENT_TIMESTAMP",
+	"DATE":               "CURRENT_DATE",
+	"TIME":               "CURRENT_TIME",
+	"INET":               "'0.0.0.0'",
+	"TEXT[]":             "'{}'",
+	"DOUBLE PRECISION[]": "'{}'",
+	"BOOLEAN[]":          "'{}'",
+	"JSONB":              "'{}'",
+}
 
-	return "", nil
+// computeFillValue computes the fill-value of the column-type, e.g. an empty string for VARCHAR(n)
+func computeFillValue(fieldType string) string {
+	if strings.HasPrefix(fieldType, "VARCHAR") {
+		return "''"
+	}
+	return fieldFillValues[fieldType]
 }
 
-func CreateAlterTable(model types.ModelType, appDb *pgxpool.Pool) error {
+// type-casts of the information_schema column-default, e.g. 'en-US'::character varying
+var defaultCastPattern = regexp.MustCompile(`::[a-z ]+(\[\])?$`)
 
+// normalizeDefault normalizes the column-default script, for comparison: without the enclosing parentheses and
+// type-casts, and case-insensitive for non-literal values, e.g. TRUE, CURRENT_TIMESTAMP
+func normalizeDefault(defaultValue string) string {
+	for {
+		defaultValue = strings.TrimSpace(defaultValue)
+		if strings.HasPrefix(defaultValue, "(") && strings.HasSuffix(defaultValue, ")") {
+			defaultValue = defaultValue[1 : len(defaultValue)-1]
+		} else if defaultCastPattern.MatchString(defaultValue) {
+			defaultValue = defaultCastPattern.ReplaceAllString(defaultValue, "")
+		} else {
+			break
+		}
+	}
+	if strings.HasPrefix(defaultValue, "'") {
+		return defaultValue
+	}
+	if strings.ToUpper(defaultValue) == "NULL" {
+		return ""
+	}
+	return strings.ToLower(defaultValue)
+}
+
+// isFieldTypeChanged checks the column-type of the table column against the column-type of the table-field
+func isFieldTypeChanged(fieldType string, column TableColumnType) bool {
+	if strings.HasPrefix(fieldType, "VARCHAR") {
+		return column.UdtName != "varchar" || fieldType != fmt.Sprintf("VARCHAR(%v)", column.MaxLength)
+	}
+	return fieldUdtNames[fieldType] != column.UdtName
+}
+
+// GetTableColumns introspects the live table columns and single-column unique constraints, by column-name.
+// The table does not exist, for empty table columns
+func GetTableColumns(tableName string, appDb *pgxpool.Pool) (map[string]TableColumnType, error) {
+	// table-schema | default: current_schema()
+	tableSchema := ""
+	if tableNames := strings.Split(tableName, "."); len(tableNames) > 1 {
+		tableSchema = tableNames[0]
+		tableName = tableNames[1]
+	}
+	columnQuery := "SELECT column_name, udt_name, COALESCE(character_maximum_length, 0), is_nullable = 'YES', COALESCE(column_default, '') FROM information_schema.columns WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND table_name = $2 ORDER BY ordinal_position"
+	rows, err := appDb.Query(context.Background(), columnQuery, tableSchema, tableName)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Db query Error [table-columns]: %v", err.Error()))
+	}
+	defer rows.Close()
+	tableColumns := map[string]TableColumnType{}
+	for rows.Next() {
+		var column TableColumnType
+		if err = rows.Scan(&column.ColumnName, &column.UdtName, &column.MaxLength, &column.IsNullable, &column.ColumnDefault); err != nil {
+			return nil, errors.New(fmt.Sprintf("Error reading table-columns: %v", err.Error()))
+		}
+		tableColumns[column.ColumnName] = column
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.New(fmt.Sprintf("Error reading table-columns: %v", err.Error()))
+	}
+	// single-column unique constraints
+	uniqueQuery := "SELECT tc.constraint_name, MIN(kcu.column_name) FROM information_schema.table_constraints tc JOIN information_schema.key_column_usage kcu ON kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name WHERE tc.constraint_type = 'UNIQUE' AND tc.table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND tc.table_name = $2 GROUP BY tc.constraint_name HAVING COUNT(*) = 1"
+	uniqueRows, err := appDb.Query(context.Background(), uniqueQuery, tableSchema, tableName)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Db query Error [unique-constraints]: %v", err.Error()))
+	}
+	defer uniqueRows.Close()
+	for uniqueRows.Next() {
+		var constraintName, columnName string
+		if err = uniqueRows.Scan(&constraintName, &columnName); err != nil {
+			return nil, errors.New(fmt.Sprintf("Error reading unique-constraints: %v", err.Error()))
+		}
+		if column, ok := tableColumns[columnName]; ok {
+			column.UniqueConstraint = constraintName
+			tableColumns[columnName] = column
+		}
+	}
+	if err = uniqueRows.Err(); err != nil {
+		return nil, errors.New(fmt.Sprintf("Error reading unique-constraints: %v", err.Error()))
+	}
+	return tableColumns, nil
+}
+
+// ComputeAlterTableQuery compose the alter-table scripts, from the diff of the model table-fields and the live
+// table columns: renamed (FormerName), dropped (DropColumns only), added and retyped columns, changed nullability,
+// defaults and unique constraints. The added NOT NULL columns, without a default-value, are filled with the
+// column-type fill-value (e.g. an empty string or 0), for the existing records. The primary-key columns are not altered
+func ComputeAlterTableQuery(model types.ModelType, tableColumns map[string]TableColumnType) ([]string, error) {
+	if model.TableName == "" || len(tableColumns) < 1 {
+		return nil, errors.New(fmt.Sprintf("table-name and existing table-columns are required to compute the alter-table script: %v", model.TableName))
+	}
+	modelFields, err := ComputeModelFields(model)
+	if err != nil {
+		return nil, err
+	}
+	primaryFields := ComputePrimaryFields(modelFields)
+	tableName := QuoteTableName(model.TableName)
+	// copy the table-columns, for the renamed columns
+	columns := map[string]TableColumnType{}
+	for columnName, column := range tableColumns {
+		columns[columnName] = column
+	}
+	var renameQueries, dropQueries, addQueries, alterQueries, indexQueries []string
+	for _, field := range modelFields {
+		if field.FieldDesc.FormerName == "" {
+			continue
+		}
+		formerName := ToSnakeCase(field.FieldDesc.FormerName)
+		column, ok := columns[formerName]
+		if _, exists := columns[field.FieldName]; !ok || exists {
+			continue
+		}
+		renameQueries = append(renameQueries, fmt.Sprintf("ALTER TABLE %v RENAME COLUMN %v TO %v", tableName, pgx.Identifier{formerName}.Sanitize(), pgx.Identifier{field.FieldName}.Sanitize()))
+		delete(columns, formerName)
+		column.ColumnName = field.FieldName
+		columns[field.FieldName] = column
+	}
+	// dropped columns, not in the model table-fields, if requested (DropColumns)
+	fieldNames := map[string]bool{}
+	for _, field := range modelFields {
+		fieldNames[field.FieldName] = true
+	}
+	var droppedColumns []string
+	for columnName := range columns {
+		if model.DropColumns && !fieldNames[columnName] {
+			droppedColumns = append(droppedColumns, columnName)
+		}
+	}
+	sort.Strings(droppedColumns)
+	for _, columnName := range droppedColumns {
+		dropQueries = append(dropQueries, fmt.Sprintf("ALTER TABLE %v DROP COLUMN %v", tableName, pgx.Identifier{columnName}.Sanitize()))
+	}
+	for _, field := range modelFields {
+		primaryKey := ArrayStringContains(primaryFields, field.FieldName)
+		fieldName := pgx.Identifier{field.FieldName}.Sanitize()
+		column, ok := columns[field.FieldName]
+		if !ok {
+			// added column
+			fieldDef, err := ComputeFieldDefinition(field, false)
+			if err != nil {
+				return nil, err
+			}
+			defaultValue, err := ComputeFieldDefault(field, primaryKey && len(primaryFields) == 1)
+			if err != nil {
+				return nil, err
+			}
+			if field.FieldDesc.AllowNull || primaryKey || defaultValue != "" {
+				addQueries = append(addQueries, fmt.Sprintf("ALTER TABLE %v ADD COLUMN %v", tableName, fieldDef))
+			} else {
+				// fill the existing records, then drop the fill-value default
+				fieldType, _ := ComputeFieldType(field.FieldDesc)
+				fillValue := computeFillValue(fieldType)
+				if fillValue == "" {
+					return nil, errors.New(fmt.Sprintf("field [%v]: a default-value is required to add the NOT NULL column", field.FieldName))
+				}
+				addQueries = append(addQueries,
+					fmt.Sprintf("ALTER TABLE %v ADD COLUMN %v DEFAULT %v", tableName, fieldDef, fillValue),
+					fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v DROP DEFAULT", tableName, fieldName),
+				)
+			}
+			if field.FieldDesc.Indexable && !field.FieldDesc.Unique {
+				indexQueries = append(indexQueries, ComputeIndexQuery(model.TableName, field.FieldName))
+			}
+			continue
+		}
+		fieldType, err := ComputeFieldType(field.FieldDesc)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("field [%v]: %v", field.FieldName, err.Error()))
+		}
+		if isFieldTypeChanged(fieldType, column) {
+			alterQueries = append(alterQueries, fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v TYPE %v USING %v::%v", tableName, fieldName, fieldType, fieldName, fieldType))
+		}
+		defaultValue, err := ComputeFieldDefault(field, primaryKey && len(primaryFields) == 1)
+		if err != nil {
+			return nil, err
+		}
+		if normalizeDefault(defaultValue) != normalizeDefault(column.ColumnDefault) {
+			if defaultValue == "" {
+				alterQueries = append(alterQueries, fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v DROP DEFAULT", tableName, fieldName))
+			} else {
+				alterQueries = append(alterQueries, fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v SET DEFAULT %v", tableName, fieldName, defaultValue))
+			}
+		}
+		if primaryKey {
+			continue
+		}
+		if field.FieldDesc.AllowNull && !column.IsNullable {
+			alterQueries = append(alterQueries, fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v DROP NOT NULL", tableName, fieldName))
+		} else if !field.FieldDesc.AllowNull && column.IsNullable {
+			alterQueries = append(alterQueries, fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v SET NOT NULL", tableName, fieldName))
+		}
+		if field.FieldDesc.Unique && column.UniqueConstraint == "" {
+			alterQueries = append(alterQueries, fmt.Sprintf("ALTER TABLE %v ADD UNIQUE (%v)", tableName, fieldName))
+		} else if !field.FieldDesc.Unique && column.UniqueConstraint != "" {
+			alterQueries = append(alterQueries, fmt.Sprintf("ALTER TABLE %v DROP CONSTRAINT %v", tableName, pgx.Identifier{column.UniqueConstraint}.Sanitize()))
+		}
+	}
+	var alterTableQueries []string
+	for _, queries := range [][]string{renameQueries, dropQueries, addQueries, alterQueries, indexQueries} {
+		alterTableQueries = append(alterTableQueries, queries...)
+	}
+	return alterTableQueries, nil
+}
+
+// CreateAlterTableQuery compose the alter-table script, from the diff of the model and the live table.
+// Returns an empty script, if the table is up-to-date with the model
+func CreateAlterTableQuery(model types.ModelType, appDb *pgxpool.Pool) (string, error) {
+	tableColumns, err := GetTableColumns(model.TableName, appDb)
+	if err != nil {
+		return "", err
+	}
+	if len(tableColumns) < 1 {
+		return "", errors.New(fmt.Sprintf("table [%v] does not exist", model.TableName))
+	}
+	alterQueries, err := ComputeAlterTableQuery(model, tableColumns)
+	if err != nil {
+		return "", err
+	}
+	if len(alterQueries) < 1 {
+		return "", nil
+	}
+	return strings.Join(alterQueries, ";\n") + ";", nil
+}
 
-	return nil
+// CreateAlterTable alters the live table, to match the model, in a transaction and returns the alter-table script.
+// For dryRun, the alter-table script is returned without executing it
+func CreateAlterTable(model types.ModelType, appDb *pgxpool.Pool, dryRun bool) (string, error) {
+	alterQuery, err := CreateAlterTableQuery(model, appDb)
+	if err != nil || dryRun || alterQuery == "" {
+		return alterQuery, err
+	}
+	tx, err := appDb.Begin(context.Background())
+	if err != nil {
+		return alterQuery, errors.New(fmt.Sprintf("Error starting the alter-table transaction: %v", err.Error()))
+	}
+	defer tx.Rollback(context.Background())
+	if _, err = tx.Exec(context.Background(), alterQuery); err != nil {
+		return alterQuery, errors.New(fmt.Sprintf("Error altering table [%v]: %v", model.TableName, err.Error()))
+	}
+	if err = tx.Commit(context.Background()); err != nil {
+		return alterQuery, errors.New(fmt.Sprintf("Error committing the alter-table transaction: %v", err.Error()))
+	}
+	return alterQuery, nil
 }
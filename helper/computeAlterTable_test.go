@@ -0,0 +1,110 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-15 | @Updated: 2021-01-15
+// @Company: mConnect.biz | @License: MIT
+// @Description: alter-table-query (schema diff) test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+// live table columns, for the createTableModel
+var serviceTableColumns = map[string]TableColumnType{
+	"category":   {ColumnName: "category", UdtName: "varchar", MaxLength: 255, IsNullable: false},
+	"cost":       {ColumnName: "cost", UdtName: "numeric", IsNullable: true},
+	"name":       {ColumnName: "name", UdtName: "varchar", MaxLength: 120, IsNullable: false, UniqueConstraint: "services_name_key"},
+	"priority":   {ColumnName: "priority", UdtName: "int4", IsNullable: false, ColumnDefault: "100"},
+	"created_at": {ColumnName: "created_at", UdtName: "timestamptz", IsNullable: false, ColumnDefault: "CURRENT_TIMESTAMP"},
+	"updated_at": {ColumnName: "updated_at", UdtName: "timestamptz", IsNullable: false, ColumnDefault: "CURRENT_TIMESTAMP"},
+}
+
+func TestComputeAlterTableQuery(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute no alter-table script, for the up-to-date table:",
+		TestFunc: func() {
+			res, err := ComputeAlterTableQuery(createTableModel, serviceTableColumns)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, len(res), 0, "alter-table scripts should be empty")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute alter-table script, for the renamed, dropped, added and changed fields:",
+		TestFunc: func() {
+			model := types.ModelType{
+				TableName: "services",
+				RecordDesc: types.RecordDescType{
+					"title": types.FieldDescType{
+						FieldType:   datatypes.String,
+						FieldLength: 120,
+						FormerName:  "name",
+					},
+					"category": types.FieldDescType{
+						FieldType: datatypes.Text,
+						AllowNull: true,
+					},
+					"priority": types.FieldDescType{
+						FieldType: datatypes.Integer,
+					},
+					"url": types.FieldDescType{
+						FieldType: datatypes.URL,
+						AllowNull: true,
+						Indexable: true,
+					},
+				},
+				TimeStamp:   true,
+				DropColumns: true,
+			}
+			res, err := ComputeAlterTableQuery(model, serviceTableColumns)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertStrictEquals(t, res, []string{
+				`ALTER TABLE "services" RENAME COLUMN "name" TO "title"`,
+				`ALTER TABLE "services" DROP COLUMN "cost"`,
+				`ALTER TABLE "services" ADD COLUMN "url" VARCHAR(255)`,
+				`ALTER TABLE "services" ALTER COLUMN "category" TYPE TEXT USING "category"::TEXT`,
+				`ALTER TABLE "services" ALTER COLUMN "category" DROP NOT NULL`,
+				`ALTER TABLE "services" ALTER COLUMN "priority" DROP DEFAULT`,
+				`ALTER TABLE "services" DROP CONSTRAINT "services_name_key"`,
+				`CREATE INDEX IF NOT EXISTS "services_url_idx" ON "services" ("url")`,
+			}, "alter-table scripts should match the model changes")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should keep the columns not in the model, and fill the added NOT NULL columns without default-value:",
+		TestFunc: func() {
+			model := types.ModelType{
+				TableName: "services",
+				RecordDesc: types.RecordDescType{
+					"name":     createTableModel.RecordDesc["name"],
+					"category": createTableModel.RecordDesc["category"],
+					"priority": createTableModel.RecordDesc["priority"],
+					"code": types.FieldDescType{
+						FieldType:   datatypes.String,
+						FieldLength: 20,
+					},
+				},
+				TimeStamp: true,
+			}
+			res, err := ComputeAlterTableQuery(model, serviceTableColumns)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertStrictEquals(t, res, []string{
+				`ALTER TABLE "services" ADD COLUMN "code" VARCHAR(20) NOT NULL DEFAULT ''`,
+				`ALTER TABLE "services" ALTER COLUMN "code" DROP DEFAULT`,
+			}, "alter-table scripts should match the model changes")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compare the type-cast column-defaults, and return error for the missing table:",
+		TestFunc: func() {
+			mctest.AssertEquals(t, normalizeDefault("'en-US'::character varying"), "'en-US'", "type-cast should be removed")
+			mctest.AssertEquals(t, normalizeDefault("('{}'::text[])"), "'{}'", "array type-cast should be removed")
+			mctest.AssertEquals(t, normalizeDefault("CURRENT_TIMESTAMP"), normalizeDefault("current_timestamp"), "non-literal defaults should be case-insensitive")
+			_, err := ComputeAlterTableQuery(createTableModel, map[string]TableColumnType{})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -0,0 +1,178 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-19 | @Updated: 2021-01-19
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute COPY (copy-create) values, chunks and temp-table scripts
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	"strings"
+	"time"
+)
+
+// COPY defaults
+const (
+	CopyThreshold = 1000
+	CopyChunkSize = 5000
+)
+
+// date/time string layouts, for the COPY date/time values
+var copyTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.000000",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// computeCopyTime converts the date/time string value to time.Time
+func computeCopyTime(fieldValue interface{}) (interface{}, error) {
+	val, ok := fieldValue.(string)
+	if !ok {
+		return fieldValue, nil
+	}
+	for _, layout := range copyTimeLayouts {
+		if timeValue, err := time.Parse(layout, val); err == nil {
+			return timeValue, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("Invalid date/time value: %v", val))
+}
+
+// ComputeCopyValue converts the field-value to the pgtype (binary) encoder of the column udt-name, e.g. uuid,
+// jsonb, timestamptz, numeric and arrays. The other column-types values are encoded by pgx, as-is
+func ComputeCopyValue(udtName string, fieldValue interface{}) (interface{}, error) {
+	if fieldValue == nil {
+		return nil, nil
+	}
+	var value pgtype.Value
+	switch udtName {
+	case "uuid":
+		value = &pgtype.UUID{}
+	case "json":
+		value = &pgtype.JSON{}
+	case "jsonb":
+		value = &pgtype.JSONB{}
+	case "timestamptz", "timestamp", "date":
+		timeValue, err := computeCopyTime(fieldValue)
+		if err != nil {
+			return nil, err
+		}
+		fieldValue = timeValue
+		switch udtName {
+		case "timestamptz":
+			value = &pgtype.Timestamptz{}
+		case "timestamp":
+			value = &pgtype.Timestamp{}
+		default:
+			value = &pgtype.Date{}
+		}
+	case "numeric":
+		value = &pgtype.Numeric{}
+	case "int2":
+		value = &pgtype.Int2{}
+	case "int4":
+		value = &pgtype.Int4{}
+	case "int8":
+		value = &pgtype.Int8{}
+	case "float4":
+		value = &pgtype.Float4{}
+	case "float8":
+		value = &pgtype.Float8{}
+	case "_text":
+		value = &pgtype.TextArray{}
+	case "_varchar":
+		value = &pgtype.VarcharArray{}
+	case "_uuid":
+		value = &pgtype.UUIDArray{}
+	case "_int4":
+		value = &pgtype.Int4Array{}
+	case "_int8":
+		value = &pgtype.Int8Array{}
+	case "_numeric":
+		value = &pgtype.NumericArray{}
+	case "_float8":
+		value = &pgtype.Float8Array{}
+	case "_bool":
+		value = &pgtype.BoolArray{}
+	default:
+		return fieldValue, nil
+	}
+	if err := value.Set(fieldValue); err != nil {
+		return nil, errors.New(fmt.Sprintf("Invalid %v value: %v | %v", udtName, fieldValue, err.Error()))
+	}
+	return value, nil
+}
+
+// ComputeCopyRows computes the COPY rows (pgtype values) of the create-records (actionParams), by the
+// table-fields order and the live table-columns types
+func ComputeCopyRows(actionParams types.ActionParamsType, tableFields []string, tableColumns map[string]TableColumnType) ([][]interface{}, error) {
+	if len(actionParams) < 1 || len(tableFields) < 1 {
+		return nil, errors.New("action-params and table-fields are required for the copy-create operation")
+	}
+	for _, fieldName := range tableFields {
+		if _, ok := tableColumns[fieldName]; !ok {
+			return nil, errors.New(fmt.Sprintf("Invalid field-name: %v | not a table-column", fieldName))
+		}
+	}
+	var copyRows [][]interface{}
+	for recNum, rec := range actionParams {
+		var copyRow []interface{}
+		for _, fieldName := range tableFields {
+			fieldValue, ok := rec[fieldName]
+			if !ok {
+				return nil, errors.New(fmt.Sprintf("Record #%v: required field_name[%v] is missing", recNum, fieldName))
+			}
+			copyValue, err := ComputeCopyValue(tableColumns[fieldName].UdtName, fieldValue)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("Record #%v, field_name[%v]: %v", recNum, fieldName, err.Error()))
+			}
+			copyRow = append(copyRow, copyValue)
+		}
+		copyRows = append(copyRows, copyRow)
+	}
+	return copyRows, nil
+}
+
+// ComputeCopyChunks splits the COPY rows into chunks, of up to chunkSize rows | default: CopyChunkSize
+func ComputeCopyChunks(copyRows [][]interface{}, chunkSize int) [][][]interface{} {
+	if chunkSize < 1 {
+		chunkSize = CopyChunkSize
+	}
+	var copyChunks [][][]interface{}
+	for start := 0; start < len(copyRows); start += chunkSize {
+		end := start + chunkSize
+		if end > len(copyRows) {
+			end = len(copyRows)
+		}
+		copyChunks = append(copyChunks, copyRows[start:end])
+	}
+	return copyChunks
+}
+
+// ComputeCopyQuery compose the COPY temp-table scripts: the records are copied into the temp-table (of the
+// table-fields types, without constraints/defaults) and inserted into the table, returning the generated ids
+func ComputeCopyQuery(tableName string, tableFields []string) (types.CopyQueryResponseType, error) {
+	if tableName == "" || len(tableFields) < 1 {
+		return types.CopyQueryResponseType{}, errors.New("table-name and table-fields are required for the copy-create operation")
+	}
+	var fields []string
+	for _, fieldName := range tableFields {
+		if !IsFieldName(fieldName) {
+			return types.CopyQueryResponseType{}, errors.New(fmt.Sprintf("Invalid field-name: %v", fieldName))
+		}
+		fields = append(fields, pgx.Identifier{fieldName}.Sanitize())
+	}
+	tableNames := strings.Split(tableName, ".")
+	copyTable := "mcorm_copy_" + tableNames[len(tableNames)-1]
+	fieldList := strings.Join(fields, ", ")
+	return types.CopyQueryResponseType{
+		CopyTable:   copyTable,
+		CreateQuery: fmt.Sprintf("CREATE TEMP TABLE %v ON COMMIT DROP AS SELECT %v FROM %v WITH NO DATA", pgx.Identifier{copyTable}.Sanitize(), fieldList, QuoteTableName(tableName)),
+		InsertQuery: fmt.Sprintf("INSERT INTO %v (%v) SELECT %v FROM %v RETURNING id", QuoteTableName(tableName), fieldList, fieldList, pgx.Identifier{copyTable}.Sanitize()),
+		ClearQuery:  fmt.Sprintf("TRUNCATE %v", pgx.Identifier{copyTable}.Sanitize()),
+	}, nil
+}
@@ -0,0 +1,101 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-19 | @Updated: 2021-01-19
+// @Company: mConnect.biz | @License: MIT
+// @Description: copy-create values, chunks and query test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mctest"
+	"github.com/jackc/pgtype"
+	"testing"
+	"time"
+)
+
+var copyTableColumns = map[string]TableColumnType{
+	"id":         {ColumnName: "id", UdtName: "uuid", IsNullable: false, ColumnDefault: "gen_random_uuid()"},
+	"name":       {ColumnName: "name", UdtName: "varchar", MaxLength: 120, IsNullable: false},
+	"owner_id":   {ColumnName: "owner_id", UdtName: "uuid", IsNullable: false},
+	"cost":       {ColumnName: "cost", UdtName: "numeric", IsNullable: true},
+	"settings":   {ColumnName: "settings", UdtName: "jsonb", IsNullable: true},
+	"tags":       {ColumnName: "tags", UdtName: "_text", IsNullable: true},
+	"created_at": {ColumnName: "created_at", UdtName: "timestamptz", IsNullable: false},
+}
+
+func TestComputeCopyRows(t *testing.T) {
+	createdAt := time.Date(2021, 1, 19, 10, 30, 0, 0, time.UTC)
+	actionParams := types.ActionParamsType{
+		{
+			"name":       "Cleaning",
+			"owner_id":   "6900d9f9-2ceb-450f-9a9e-527eb66c962f",
+			"cost":       120.5,
+			"settings":   map[string]interface{}{"weekly": true},
+			"tags":       []string{"home", "office"},
+			"created_at": createdAt,
+		},
+		{
+			"name":       "Laundry",
+			"owner_id":   "6900d9f9-2ceb-450f-9a9e-527eb66c962f",
+			"cost":       nil,
+			"settings":   `{"weekly": false}`,
+			"tags":       []interface{}{"home"},
+			"created_at": "2021-01-19T10:30:00Z",
+		},
+	}
+	tableFields := []string{"name", "owner_id", "cost", "settings", "tags", "created_at"}
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute copy-rows, with the pgtype encoders of the table-columns:",
+		TestFunc: func() {
+			res, err := ComputeCopyRows(actionParams, tableFields, copyTableColumns)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, len(res), 2, "copy-rows length should be: 2")
+			mctest.AssertEquals(t, res[0][0], "Cleaning", "varchar value should be: as-is")
+			ownerId, ok := res[0][1].(*pgtype.UUID)
+			mctest.AssertEquals(t, ok, true, "uuid value should be: *pgtype.UUID")
+			mctest.AssertEquals(t, ownerId.Status, pgtype.Present, "uuid value status should be: present")
+			_, ok = res[0][2].(*pgtype.Numeric)
+			mctest.AssertEquals(t, ok, true, "numeric value should be: *pgtype.Numeric")
+			mctest.AssertEquals(t, res[1][2], nil, "null numeric value should be: nil")
+			settings, ok := res[0][3].(*pgtype.JSONB)
+			mctest.AssertEquals(t, ok, true, "jsonb value should be: *pgtype.JSONB")
+			mctest.AssertEquals(t, string(settings.Bytes), `{"weekly":true}`, "jsonb value should be: marshalled map")
+			tags, ok := res[1][4].(*pgtype.TextArray)
+			mctest.AssertEquals(t, ok, true, "text-array value should be: *pgtype.TextArray")
+			mctest.AssertEquals(t, len(tags.Elements), 1, "text-array elements length should be: 1")
+			createdAtValue, ok := res[1][5].(*pgtype.Timestamptz)
+			mctest.AssertEquals(t, ok, true, "timestamptz value should be: *pgtype.Timestamptz")
+			mctest.AssertEquals(t, createdAtValue.Time.Equal(createdAt), true, "timestamptz string value should be parsed")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return error, for the invalid uuid value and the unknown table-field:",
+		TestFunc: func() {
+			_, err := ComputeCopyRows(types.ActionParamsType{{"owner_id": "not-a-uuid"}}, []string{"owner_id"}, copyTableColumns)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			_, err = ComputeCopyRows(actionParams, []string{"name", "priority"}, copyTableColumns)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute copy-chunks, of up to chunk-size rows:",
+		TestFunc: func() {
+			copyRows := [][]interface{}{{1}, {2}, {3}, {4}, {5}}
+			res := ComputeCopyChunks(copyRows, 2)
+			mctest.AssertEquals(t, len(res), 3, "copy-chunks length should be: 3")
+			mctest.AssertEquals(t, len(res[2]), 1, "last copy-chunk length should be: 1")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute copy temp-table, insert and clear scripts:",
+		TestFunc: func() {
+			res, err := ComputeCopyQuery("app.services", []string{"name", "cost"})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.CopyTable, "mcorm_copy_services", "copy-table should be: mcorm_copy_services")
+			mctest.AssertEquals(t, res.CreateQuery, `CREATE TEMP TABLE "mcorm_copy_services" ON COMMIT DROP AS SELECT "name", "cost" FROM "app"."services" WITH NO DATA`, "create-query should match")
+			mctest.AssertEquals(t, res.InsertQuery, `INSERT INTO "app"."services" ("name", "cost") SELECT "name", "cost" FROM "mcorm_copy_services" RETURNING id`, "insert-query should match")
+			mctest.AssertEquals(t, res.ClearQuery, `TRUNCATE "mcorm_copy_services"`, "clear-query should match")
+		},
+	})
+
+	mctest.PostTestResult()
+}
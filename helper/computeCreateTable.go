@@ -1,21 +1,309 @@
-// @Author: abbeymart | Abi Akindele | @Created: 2020-12-08 | @Updated: 2020-12-08
+// @Author: abbeymart | Abi Akindele | @Created: 2020-12-08 | @Updated: 2021-01-15
 // @Company: mConnect.biz | @License: MIT
 // @Description: compute create-table script
 
 package helper
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
-func CreateTableQuery(model types.ModelType) (string, error)  {
 
+// default VARCHAR length, for string-based data-types
+const defaultFieldLength = 255
 
-	return "", nil
+// ModelFieldType describes a table column: field-name (snake_case) and the field-description
+type ModelFieldType struct {
+	FieldName string
+	FieldDesc types.FieldDescType
 }
 
-func CreateTable(model types.ModelType, appDb *pgxpool.Pool) error {
+// ComputeModelFields computes the ordered table-fields of the model: id, the record-description fields (sorted)
+// and the base-model fields (language, desc, appId) and stamp-fields (isActive, createdBy/updatedBy,
+// createdAt/updatedAt, version and deletedAt), as requested by the model flags. The record-description overrides
+// the base-model fields
+func ComputeModelFields(model types.ModelType) ([]ModelFieldType, error) {
+	var modelFields []ModelFieldType
+	fieldNames := map[string]string{}
+	addField := func(key string, fieldDesc types.FieldDescType) error {
+		fieldName := ToSnakeCase(key)
+		if !IsFieldName(fieldName) {
+			return errors.New(fmt.Sprintf("Invalid field-name: %v", key))
+		}
+		if prevKey, ok := fieldNames[fieldName]; ok {
+			return errors.New(fmt.Sprintf("Duplicate field-name: %v [%v]", key, prevKey))
+		}
+		fieldNames[fieldName] = key
+		modelFields = append(modelFields, ModelFieldType{FieldName: fieldName, FieldDesc: fieldDesc})
+		return nil
+	}
+	// base-model/stamp-fields, by model flags
+	var baseFields []string
+	if model.IncludeBaseModel {
+		baseFields = append(baseFields, "language", "desc", "appId")
+	}
+	if model.IncludeBaseModel || model.ActiveStamp {
+		baseFields = append(baseFields, "isActive")
+	}
+	if model.IncludeBaseModel || model.ActorStamp {
+		baseFields = append(baseFields, "createdBy", "updatedBy")
+	}
+	if model.IncludeBaseModel || model.TimeStamp {
+		baseFields = append(baseFields, "createdAt", "updatedAt")
+	}
+	if model.VersionStamp {
+		baseFields = append(baseFields, "version")
+	}
+	if model.SoftDelete {
+		baseFields = append(baseFields, "deletedAt")
+	}
+	// id-field
+	if fieldDesc, ok := model.RecordDesc["id"]; ok {
+		if err := addField("id", fieldDesc); err != nil {
+			return nil, err
+		}
+	} else if model.IncludeBaseModel {
+		if err := addField("id", types.BaseModel["id"]); err != nil {
+			return nil, err
+		}
+	}
+	// record-description fields, excluding the id and base-model fields
+	var keys []string
+	for key := range model.RecordDesc {
+		if key != "id" && !ArrayStringContains(baseFields, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := addField(key, model.RecordDesc[key]); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range baseFields {
+		fieldDesc, ok := model.RecordDesc[key]
+		if !ok {
+			fieldDesc = types.BaseModel[key]
+		}
+		if err := addField(key, fieldDesc); err != nil {
+			return nil, err
+		}
+	}
+	if len(modelFields) < 1 {
+		return nil, errors.New("record-description or base-model fields are required to compute the table-fields")
+	}
+	return modelFields, nil
+}
+
+// ComputeFieldType computes the PostgreSQL column-type of the field-description data-type
+func ComputeFieldType(fieldDesc types.FieldDescType) (string, error) {
+	fieldLength := fieldDesc.FieldLength
+	if fieldLength < 1 {
+		fieldLength = defaultFieldLength
+	}
+	switch fieldDesc.FieldType {
+	case datatypes.String, datatypes.StringAlpha, datatypes.StringAlphaNumeric, datatypes.PostalCode,
+		datatypes.MongoDBId, datatypes.MD4, datatypes.MD5, datatypes.SHA1, datatypes.SHA256, datatypes.SHA384,
+		datatypes.SHA512, datatypes.Email, datatypes.URL, datatypes.DomainName, datatypes.LatitudeLongitude,
+		datatypes.ISO2, datatypes.ISO3, datatypes.MACAddress, datatypes.Mime, datatypes.CreditCard,
+		datatypes.Currency, datatypes.IMEI:
+		return fmt.Sprintf("VARCHAR(%v)", fieldLength), nil
+	case datatypes.Text, datatypes.JWT:
+		return "TEXT", nil
+	case datatypes.UUID, datatypes.UUID3, datatypes.UUID4, datatypes.UUID5:
+		return "UUID", nil
+	case datatypes.Integer, datatypes.Positive, datatypes.Natural, datatypes.Negative, datatypes.Port:
+		return "INTEGER", nil
+	case datatypes.BigInt:
+		return "BIGINT", nil
+	case datatypes.Decimal:
+		return "NUMERIC", nil
+	case datatypes.Float32:
+		return "REAL", nil
+	case datatypes.Number, datatypes.Float, datatypes.Float64, datatypes.BigFloat, datatypes.Latitude,
+		datatypes.Longitude:
+		return "DOUBLE PRECISION", nil
+	case datatypes.Boolean:
+		return "BOOLEAN", nil
+	case datatypes.DateTime, datatypes.TimeStampZ:
+		return "TIMESTAMPTZ", nil
+	case datatypes.TimeStamp:
+		return "TIMESTAMP", nil
+	case datatypes.Date:
+		return "DATE", nil
+	case datatypes.Time:
+		return "TIME", nil
+	case datatypes.IP, datatypes.IP4, datatypes.IP6:
+		return "INET", nil
+	case datatypes.ArrayOfString:
+		return "TEXT[]", nil
+	case datatypes.ArrayOfNumber:
+		return "DOUBLE PRECISION[]", nil
+	case datatypes.ArrayOfBoolean:
+		return "BOOLEAN[]", nil
+	case datatypes.JSON, datatypes.Object, datatypes.Map, datatypes.Set, datatypes.Array, datatypes.ArrayOfStruct,
+		datatypes.ArrayOfMap, datatypes.ArrayOfArray:
+		return "JSONB", nil
+	default:
+		return "", errors.New(fmt.Sprintf("Unknown or unsupported field-type: %v", fieldDesc.FieldType))
+	}
+}
+
+// ComputeDefaultValue computes the column DEFAULT script of the field-description default-value. A time-value
+// default (e.g. time.Now) is computed at insert, as the CURRENT_TIMESTAMP
+func ComputeDefaultValue(fieldDesc types.FieldDescType) (string, error) {
+	if fieldDesc.DefaultValue == nil {
+		return "", nil
+	}
+	switch val := fieldDesc.DefaultValue().(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return "'" + strings.ReplaceAll(val, "'", "''") + "'", nil
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(val)), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%v", val), nil
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case time.Time:
+		return "CURRENT_TIMESTAMP", nil
+	default:
+		return "", errors.New(fmt.Sprintf("Unsupported default-value type: %T", val))
+	}
+}
 
+// ComputeFieldDefault computes the column DEFAULT script of the table-field. A uuid primary-key, with no
+// default-value, defaults to gen_random_uuid()
+func ComputeFieldDefault(field ModelFieldType, primaryKey bool) (string, error) {
+	defaultValue, err := ComputeDefaultValue(field.FieldDesc)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("field [%v]: %v", field.FieldName, err.Error()))
+	}
+	if primaryKey && defaultValue == "" && field.FieldDesc.FieldType == datatypes.UUID {
+		defaultValue = "gen_random_uuid()"
+	}
+	return defaultValue, nil
+}
 
+// ComputeFieldDefinition computes the column-definition script of the table-field, e.g.
+// "email" VARCHAR(120) NOT NULL UNIQUE. The field-names are quoted, for reserved words (e.g. desc)
+func ComputeFieldDefinition(field ModelFieldType, primaryKey bool) (string, error) {
+	fieldType, err := ComputeFieldType(field.FieldDesc)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("field [%v]: %v", field.FieldName, err.Error()))
+	}
+	defaultValue, err := ComputeFieldDefault(field, primaryKey)
+	if err != nil {
+		return "", err
+	}
+	fieldDef := pgx.Identifier{field.FieldName}.Sanitize() + " " + fieldType
+	if primaryKey {
+		fieldDef += " PRIMARY KEY"
+	} else {
+		if !field.FieldDesc.AllowNull {
+			fieldDef += " NOT NULL"
+		}
+		if field.FieldDesc.Unique {
+			fieldDef += " UNIQUE"
+		}
+	}
+	if defaultValue != "" {
+		fieldDef += " DEFAULT " + defaultValue
+	}
+	return fieldDef, nil
+}
+
+// ComputeIndexQuery compose the create-index script of the table-field, named tableName_fieldName_idx
+func ComputeIndexQuery(tableName string, fieldName string) string {
+	tableNames := strings.Split(tableName, ".")
+	indexName := pgx.Identifier{tableNames[len(tableNames)-1] + "_" + fieldName + "_idx"}.Sanitize()
+	return fmt.Sprintf("CREATE INDEX IF NOT EXISTS %v ON %v (%v)", indexName, QuoteTableName(tableName), pgx.Identifier{fieldName}.Sanitize())
+}
+
+// ComputePrimaryFields computes the primary-key fields of the table-fields | default: id
+func ComputePrimaryFields(modelFields []ModelFieldType) []string {
+	var primaryFields []string
+	for _, field := range modelFields {
+		if field.FieldDesc.PrimaryKey {
+			primaryFields = append(primaryFields, field.FieldName)
+		}
+	}
+	if len(primaryFields) == 0 {
+		for _, field := range modelFields {
+			if field.FieldName == "id" {
+				primaryFields = append(primaryFields, field.FieldName)
+			}
+		}
+	}
+	return primaryFields
+}
+
+// ComputeCreateTableQuery compose the create-table script and the create-index scripts of the indexable fields,
+// from the model record-description
+func ComputeCreateTableQuery(model types.ModelType) (string, []string, error) {
+	if model.TableName == "" {
+		return "", nil, errors.New("table-name is required to compute the create-table script")
+	}
+	modelFields, err := ComputeModelFields(model)
+	if err != nil {
+		return "", nil, err
+	}
+	primaryFields := ComputePrimaryFields(modelFields)
+	var fieldDefs []string
+	var indexQueries []string
+	for _, field := range modelFields {
+		// a single primary-key is defined with the column, composite primary-keys as a table-constraint
+		fieldDef, err := ComputeFieldDefinition(field, len(primaryFields) == 1 && primaryFields[0] == field.FieldName)
+		if err != nil {
+			return "", nil, err
+		}
+		fieldDefs = append(fieldDefs, fieldDef)
+		if field.FieldDesc.Indexable && !field.FieldDesc.Unique && !ArrayStringContains(primaryFields, field.FieldName) {
+			indexQueries = append(indexQueries, ComputeIndexQuery(model.TableName, field.FieldName))
+		}
+	}
+	if len(primaryFields) > 1 {
+		var primaryKeys []string
+		for _, fieldName := range primaryFields {
+			primaryKeys = append(primaryKeys, pgx.Identifier{fieldName}.Sanitize())
+		}
+		fieldDefs = append(fieldDefs, fmt.Sprintf("PRIMARY KEY (%v)", strings.Join(primaryKeys, ", ")))
+	}
+	createQuery := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %v (\n\t%v\n)", QuoteTableName(model.TableName), strings.Join(fieldDefs, ",\n\t"))
+	return createQuery, indexQueries, nil
+}
+
+// CreateTableQuery compose the create-table script, including the create-index scripts of the indexable
+// fields, from the model record-description
+func CreateTableQuery(model types.ModelType) (string, error) {
+	createQuery, indexQueries, err := ComputeCreateTableQuery(model)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(append([]string{createQuery}, indexQueries...), ";\n") + ";", nil
+}
+
+// CreateTable creates the model table (if not exists) and the indexes, from the model record-description
+func CreateTable(model types.ModelType, appDb *pgxpool.Pool) error {
+	createQuery, err := CreateTableQuery(model)
+	if err != nil {
+		return err
+	}
+	// the create-table and create-index scripts run as a single (implicit) transaction
+	if _, err = appDb.Exec(context.Background(), createQuery); err != nil {
+		return errors.New(fmt.Sprintf("Error creating table [%v]: %v", model.TableName, err.Error()))
+	}
 	return nil
-}
\ No newline at end of file
+}
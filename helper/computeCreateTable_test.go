@@ -0,0 +1,101 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-15 | @Updated: 2021-01-15
+// @Company: mConnect.biz | @License: MIT
+// @Description: create-table-query test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+func defaultPriority() interface{} { return 100 }
+
+var createTableModel = types.ModelType{
+	TableName: "services",
+	RecordDesc: types.RecordDescType{
+		"name": types.FieldDescType{
+			FieldType:   datatypes.String,
+			FieldLength: 120,
+			Unique:      true,
+		},
+		"category": types.FieldDescType{
+			FieldType: datatypes.String,
+			Indexable: true,
+		},
+		"priority": types.FieldDescType{
+			FieldType:    datatypes.Integer,
+			DefaultValue: defaultPriority,
+		},
+		"cost": types.FieldDescType{
+			FieldType: datatypes.Decimal,
+			AllowNull: true,
+		},
+	},
+	TimeStamp: true,
+}
+
+func TestCreateTableQuery(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute create-table script, with the stamp-fields and indexes:",
+		TestFunc: func() {
+			res, err := CreateTableQuery(createTableModel)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, `CREATE TABLE IF NOT EXISTS "services" (
+	"category" VARCHAR(255) NOT NULL,
+	"cost" NUMERIC,
+	"name" VARCHAR(120) NOT NULL UNIQUE,
+	"priority" INTEGER NOT NULL DEFAULT 100,
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	"updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "services_category_idx" ON "services" ("category");`, "create-table script should match the model")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute create-table script, with the base-model fields:",
+		TestFunc: func() {
+			model := types.ModelType{
+				TableName:        "app.groups",
+				RecordDesc:       types.RecordDescType{"name": types.FieldDescType{FieldType: datatypes.String}},
+				IncludeBaseModel: true,
+			}
+			res, err := CreateTableQuery(model)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, `CREATE TABLE IF NOT EXISTS "app"."groups" (
+	"id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	"name" VARCHAR(255) NOT NULL,
+	"language" VARCHAR(12) NOT NULL DEFAULT 'en-US',
+	"desc" VARCHAR(255),
+	"app_id" VARCHAR(255),
+	"is_active" BOOLEAN NOT NULL DEFAULT TRUE,
+	"created_by" UUID,
+	"updated_by" UUID,
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	"updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);`, "create-table script should include the base-model fields")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute composite primary-key, and return error for unknown field-type:",
+		TestFunc: func() {
+			model := types.ModelType{
+				TableName: "user_roles",
+				RecordDesc: types.RecordDescType{
+					"userId": types.FieldDescType{FieldType: datatypes.UUID, PrimaryKey: true},
+					"roleId": types.FieldDescType{FieldType: datatypes.UUID, PrimaryKey: true},
+				},
+			}
+			res, err := CreateTableQuery(model)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, "CREATE TABLE IF NOT EXISTS \"user_roles\" (\n\t\"role_id\" UUID NOT NULL,\n\t\"user_id\" UUID NOT NULL,\n\tPRIMARY KEY (\"role_id\", \"user_id\")\n);", "primary-key should be a table-constraint")
+			model.RecordDesc["location"] = types.FieldDescType{FieldType: datatypes.Unknown}
+			_, err = CreateTableQuery(model)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -0,0 +1,152 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-11 | @Updated: 2021-01-11
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute keyset (cursor) pagination scripts and cursors
+
+package helper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cursorValueType holds a typed sort-field-value, for lossless cursor decoding
+type cursorValueType struct {
+	Type  string `json:"t"`
+	Value string `json:"v"`
+}
+
+// cursorType is the (opaque) cursor content: the sort-fields signature and the last record's sort-field-values
+type cursorType struct {
+	Sort   string            `json:"s"`
+	Values []cursorValueType `json:"v"`
+}
+
+// computeCursorSort computes the sort-fields signature, e.g. name:1,id:1
+func computeCursorSort(sortItems types.SortParamType) string {
+	var sortFields []string
+	for _, item := range sortItems {
+		sortFields = append(sortFields, fmt.Sprintf("%v:%v", item.FieldName, item.SortOrder))
+	}
+	return strings.Join(sortFields, ",")
+}
+
+// ComputeNextCursor function computes the opaque next-cursor from the sort-field-values of the last record
+// of the current page. The sortItems must include the id tie-breaker (see ComputeSortItems)
+func ComputeNextCursor(sortItems types.SortParamType, sortValues []interface{}) (string, error) {
+	if len(sortItems) < 1 || len(sortItems) != len(sortValues) {
+		return "", errors.New("sort-items and sort-values of the same length are required to compute the cursor")
+	}
+	cursor := cursorType{Sort: computeCursorSort(sortItems)}
+	for i, val := range sortValues {
+		var cursorValue cursorValueType
+		switch v := val.(type) {
+		case time.Time:
+			cursorValue = cursorValueType{Type: "time", Value: v.Format(time.RFC3339Nano)}
+		case string:
+			cursorValue = cursorValueType{Type: "string", Value: v}
+		case bool:
+			cursorValue = cursorValueType{Type: "bool", Value: strconv.FormatBool(v)}
+		case int, int8, int16, int32, int64:
+			cursorValue = cursorValueType{Type: "int", Value: fmt.Sprintf("%v", v)}
+		case uint, uint8, uint16, uint32, uint64:
+			cursorValue = cursorValueType{Type: "uint", Value: fmt.Sprintf("%v", v)}
+		case float32, float64:
+			cursorValue = cursorValueType{Type: "float", Value: fmt.Sprintf("%v", v)}
+		default:
+			return "", errors.New(fmt.Sprintf("Unsupported cursor sort-field[%v] type for value: %v", sortItems[i].FieldName, reflect.TypeOf(val)))
+		}
+		cursor.Values = append(cursor.Values, cursorValue)
+	}
+	cursorJson, err := json.Marshal(cursor)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("error computing the cursor: %v", err.Error()))
+	}
+	return base64.RawURLEncoding.EncodeToString(cursorJson), nil
+}
+
+// decodeCursor decodes the cursor sort-field-values, validated against the current sortItems
+func decodeCursor(sortItems types.SortParamType, cursor string) ([]interface{}, error) {
+	cursorJson, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	var cursorValue cursorType
+	if err = json.Unmarshal(cursorJson, &cursorValue); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	if cursorValue.Sort != computeCursorSort(sortItems) || len(cursorValue.Values) != len(sortItems) {
+		return nil, errors.New("the cursor does not match the sort-params")
+	}
+	var sortValues []interface{}
+	for _, val := range cursorValue.Values {
+		var (
+			sortValue interface{}
+			valErr    error
+		)
+		switch val.Type {
+		case "time":
+			sortValue, valErr = time.Parse(time.RFC3339Nano, val.Value)
+		case "string":
+			sortValue = val.Value
+		case "bool":
+			sortValue, valErr = strconv.ParseBool(val.Value)
+		case "int":
+			sortValue, valErr = strconv.ParseInt(val.Value, 10, 64)
+		case "uint":
+			sortValue, valErr = strconv.ParseUint(val.Value, 10, 64)
+		case "float":
+			sortValue, valErr = strconv.ParseFloat(val.Value, 64)
+		default:
+			valErr = errors.New(val.Type)
+		}
+		if valErr != nil {
+			return nil, errors.New("invalid cursor value")
+		}
+		sortValues = append(sortValues, sortValue)
+	}
+	return sortValues, nil
+}
+
+// ComputeCursorQuery function computes the keyset condition (without the WHERE keyword), for the records after
+// the cursor, as placeholder-values numbered from fieldLength+1, e.g. for sort-fields (a ASC, id DESC):
+// (a > $1 OR (a = $1 AND id < $2)). The sortItems must include the id tie-breaker (see ComputeSortItems)
+func ComputeCursorQuery(sortItems types.SortParamType, cursor string, fieldLength int) (types.WhereQueryResponseType, error) {
+	sortValues, err := decodeCursor(sortItems, cursor)
+	if err != nil {
+		return types.WhereQueryResponseType{}, err
+	}
+	var orScripts []string
+	for i, item := range sortItems {
+		if !IsFieldName(item.FieldName) {
+			return types.WhereQueryResponseType{}, errors.New(fmt.Sprintf("Invalid field-name: %v", item.FieldName))
+		}
+		if item.NullsOrder != "" {
+			return types.WhereQueryResponseType{}, errors.New(fmt.Sprintf("nulls-order is not supported for cursor pagination, field-name: %v", item.FieldName))
+		}
+		var andScripts []string
+		for j := 0; j < i; j++ {
+			andScripts = append(andScripts, fmt.Sprintf("%v = $%v", sortItems[j].FieldName, fieldLength+j+1))
+		}
+		sqlOp := ">"
+		if item.SortOrder == -1 {
+			sqlOp = "<"
+		}
+		andScripts = append(andScripts, fmt.Sprintf("%v %v $%v", item.FieldName, sqlOp, fieldLength+i+1))
+		if len(andScripts) > 1 {
+			orScripts = append(orScripts, "("+strings.Join(andScripts, " AND ")+")")
+		} else {
+			orScripts = append(orScripts, andScripts[0])
+		}
+	}
+	return types.WhereQueryResponseType{
+		WhereQuery:  "(" + strings.Join(orScripts, " OR ") + ")",
+		FieldValues: sortValues,
+	}, nil
+}
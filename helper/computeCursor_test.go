@@ -0,0 +1,45 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-11 | @Updated: 2021-01-11
+// @Company: mConnect.biz | @License: MIT
+// @Description: keyset (cursor) pagination test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mctest"
+	"testing"
+	"time"
+)
+
+func TestComputeCursorQuery(t *testing.T) {
+	sortItems := ComputeSortItems(types.SortParamType{
+		{FieldName: "created_at", SortOrder: -1},
+		{FieldName: "age", SortOrder: 1},
+	})
+	createdAt := time.Date(2021, 1, 11, 10, 30, 0, 500, time.UTC)
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the keyset-condition from the next-cursor:",
+		TestFunc: func() {
+			cursor, err := ComputeNextCursor(sortItems, []interface{}{createdAt, 21, "6900d9f9-2ceb-450f-9a9e-527eb66c962f"})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			res, err := ComputeCursorQuery(sortItems, cursor, 2)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.WhereQuery, "(created_at < $3 OR (created_at = $3 AND age > $4) OR (created_at = $3 AND age = $4 AND id > $5))", "keyset-condition should match the sort-items")
+			mctest.AssertEquals(t, len(res.FieldValues), 3, "field-values count should be: 3")
+			mctest.AssertEquals(t, res.FieldValues[0].(time.Time).Equal(createdAt), true, "time-value should be decoded as time.Time")
+			mctest.AssertEquals(t, res.FieldValues[1], int64(21), "integer-value should be decoded as int64")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return an error for a cursor of different sort-params or an invalid cursor:",
+		TestFunc: func() {
+			cursor, _ := ComputeNextCursor(sortItems, []interface{}{createdAt, 21, "6900d9f9-2ceb-450f-9a9e-527eb66c962f"})
+			_, err := ComputeCursorQuery(ComputeSortItems(types.SortParamType{{FieldName: "age", SortOrder: 1}}), cursor, 0)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			_, err = ComputeCursorQuery(sortItems, "not-a-cursor", 0)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -11,33 +11,35 @@ import (
 )
 
 // ComputeDeleteQueryById function computes delete SQL script by id(s)
-func ComputeDeleteQueryById(tableName string, recordIds []string) (string, error) {
+func ComputeDeleteQueryById(tableName string, recordIds []string) (types.DeleteQueryResponseType, error) {
 	if tableName == "" || len(recordIds) < 1 {
-		return "", errors.New("table/collection name and doc-Ids are required for the delete-by-id operation")
+		return types.DeleteQueryResponseType{}, errors.New("table/collection name and doc-Ids are required for the delete-by-id operation")
 	}
-	// validated recordIds, strictly contains string/UUID values, to avoid SQL-injection
-	// from / where condition (where-in-values)
-	whereIds := ""
-	idLen := len(recordIds)
-	for idCount, id := range recordIds {
-		whereIds += "'" + id + "'"
-		if idLen > 1 && idCount < idLen-1 {
-			whereIds += ", "
-		}
+	// from / where condition (id = ANY($1)), recordIds are passed as placeholder-value, to avoid SQL-injection
+	whereRes, err := ComputeWhereQueryByIds(recordIds, 0)
+	if err != nil {
+		return types.DeleteQueryResponseType{}, errors.New(fmt.Sprintf("error computing where-query condition(s): %v", err.Error()))
 	}
-	deleteQuery := "DELETE FROM " + tableName + " WHERE id IN(" + whereIds + ")"
-	return deleteQuery, nil
+	return types.DeleteQueryResponseType{
+		DeleteQuery: fmt.Sprintf("DELETE FROM %v %v", tableName, whereRes.WhereQuery),
+		WhereQuery:  whereRes.WhereQuery,
+		FieldValues: whereRes.FieldValues,
+	}, nil
 }
 
 // ComputeDeleteQueryByParam function computes delete SQL script by parameter specifications
-func ComputeDeleteQueryByParam(tableName string, where types.QueryParamType) (string, error) {
+func ComputeDeleteQueryByParam(tableName string, where types.QueryParamType) (types.DeleteQueryResponseType, error) {
 	if tableName == "" || len(where) < 1 {
-		return "", errors.New("table/collection name and where/query-condition are required for the delete-by-param operation")
+		return types.DeleteQueryResponseType{}, errors.New("table/collection name and where/query-condition are required for the delete-by-param operation")
 	}
-	if whereParam, err := ComputeWhereQuery(where); err == nil {
-		deleteScript := fmt.Sprintf("DELETE FROM %v %v", tableName, whereParam)
-		return deleteScript, nil
+	if whereRes, err := ComputeWhereQuery(where, 0); err == nil {
+		deleteScript := fmt.Sprintf("DELETE FROM %v %v", tableName, whereRes.WhereQuery)
+		return types.DeleteQueryResponseType{
+			DeleteQuery: deleteScript,
+			WhereQuery:  whereRes.WhereQuery,
+			FieldValues: whereRes.FieldValues,
+		}, nil
 	} else {
-		return "", errors.New(fmt.Sprintf("error computing where-query condition(s): %v", err.Error()))
+		return types.DeleteQueryResponseType{}, errors.New(fmt.Sprintf("error computing where-query condition(s): %v", err.Error()))
 	}
 }
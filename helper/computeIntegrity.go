@@ -0,0 +1,103 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-22 | @Updated: 2021-01-22
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute referential-integrity (OnDelete) action-SQL scripts, of the child-relations
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/ormActions"
+	"github.com/abbeymart/mcorm/types/ormRelations"
+	"strings"
+)
+
+// MaxRelationDepth limits the depth of the cascaded (descendant) relations, e.g. for the cyclic relations
+const MaxRelationDepth = 16
+
+// ComputeDeleteActionQueries computes the OnDelete action-queries of the child-relations of the table records that
+// met the where-query: the restrict (exists-check) queries first, then the cascade (descendants first), null and
+// default queries, to be performed prior to the delete-query, in the same transaction.
+// For the soft-delete (softDelete), the cascade soft-deletes the child-records of the soft-delete child-models only,
+// and the null and default actions are deferred to the purge, to keep the soft-deleted records restorable
+func ComputeDeleteActionQueries(tableName string, childRelations []types.ModelRelationType, whereQuery string, softDelete bool) ([]types.RelationActionQueryType, error) {
+	var restrictQueries, actionQueries []types.RelationActionQueryType
+	if err := computeDeleteActions(tableName, childRelations, whereQuery, softDelete, 0, &restrictQueries, &actionQueries); err != nil {
+		return nil, err
+	}
+	return append(restrictQueries, actionQueries...), nil
+}
+
+// computeDeleteActions computes the OnDelete action-queries of the relations, recursively for the cascade actions
+func computeDeleteActions(tableName string, relations []types.ModelRelationType, whereQuery string, softDelete bool, depth int, restrictQueries *[]types.RelationActionQueryType, actionQueries *[]types.RelationActionQueryType) error {
+	if depth >= MaxRelationDepth {
+		return errors.New(fmt.Sprintf("cascaded relations depth exceeds %v, check for the cyclic relations of the table: %v", MaxRelationDepth, tableName))
+	}
+	for _, relation := range relations {
+		action := strings.ToLower(relation.OnDelete)
+		if relation.SourceTable != tableName || action == "" || action == ormActions.NoAction {
+			continue
+		}
+		relationName := ComputeRelationName(relation)
+		// child-records: the target-records | the relation-table records, for many-to-many
+		isManyToMany := strings.ToLower(relation.RelationType) == ormRelations.ManyToMany
+		childTable, childField := relation.TargetTable, relation.TargetField
+		if isManyToMany {
+			childTable, childField = ComputeRelationTable(relation), relation.ForeignField
+		}
+		if childTable == "" || !IsFieldName(childField) || !IsFieldName(relation.SourceField) {
+			return errors.New(fmt.Sprintf("child-table, valid source-field and child-field are required for the relation: %v", relationName))
+		}
+		childWhere := fmt.Sprintf("WHERE %v IN (SELECT %v FROM %v %v)", childField, relation.SourceField, tableName, whereQuery)
+		// active (not soft-deleted) child-records, of the soft-delete child-model
+		childSoftDelete := !isManyToMany && relation.TargetModel.SoftDelete
+		activeChildWhere := childWhere
+		if childSoftDelete {
+			activeChildWhere = fmt.Sprintf("%v AND %v IS NULL", childWhere, ComputeSoftDeleteField(relation.TargetModel))
+		}
+		actionQuery := types.RelationActionQueryType{
+			RelationName: relationName,
+			ChildTable:   childTable,
+			Action:       action,
+		}
+		switch action {
+		case ormActions.Restrict:
+			actionQuery.ActionQuery = fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %v %v)", childTable, activeChildWhere)
+			*restrictQueries = append(*restrictQueries, actionQuery)
+		case ormActions.Cascade:
+			if softDelete && !childSoftDelete {
+				continue
+			}
+			cascadeWhere := childWhere
+			if softDelete {
+				cascadeWhere = activeChildWhere
+			}
+			// cascade the descendants first, the child-records are the source of the descendants
+			if !isManyToMany {
+				if err := computeDeleteActions(childTable, relation.TargetModel.Relations, cascadeWhere, softDelete, depth+1, restrictQueries, actionQueries); err != nil {
+					return err
+				}
+			}
+			if softDelete {
+				actionQuery.ActionQuery = fmt.Sprintf("UPDATE %v SET %v=CURRENT_TIMESTAMP %v", childTable, ComputeSoftDeleteField(relation.TargetModel), cascadeWhere)
+			} else {
+				actionQuery.ActionQuery = fmt.Sprintf("DELETE FROM %v %v", childTable, cascadeWhere)
+			}
+			*actionQueries = append(*actionQueries, actionQuery)
+		case ormActions.Null, ormActions.Default:
+			if softDelete {
+				continue
+			}
+			setValue := "NULL"
+			if action == ormActions.Default {
+				setValue = "DEFAULT"
+			}
+			actionQuery.ActionQuery = fmt.Sprintf("UPDATE %v SET %v = %v %v", childTable, childField, setValue, childWhere)
+			*actionQueries = append(*actionQueries, actionQuery)
+		default:
+			return errors.New(fmt.Sprintf("Unknown or unsupported OnDelete action [%v] for the relation: %v", relation.OnDelete, relationName))
+		}
+	}
+	return nil
+}
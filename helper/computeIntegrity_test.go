@@ -0,0 +1,71 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-22 | @Updated: 2021-01-22
+// @Company: mConnect.biz | @License: MIT
+// @Description: referential-integrity (OnDelete) action-queries test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/ormActions"
+	"github.com/abbeymart/mcorm/types/ormRelations"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+func TestComputeDeleteActionQueries(t *testing.T) {
+	whereQuery := "WHERE id = ANY($1)"
+	lineModel := types.ModelType{
+		TableName: "order_lines",
+		Relations: []types.ModelRelationType{
+			{SourceTable: "order_lines", TargetTable: "line_notes", SourceField: "id", TargetField: "line_id", RelationType: ormRelations.OneToMany, OnDelete: ormActions.Cascade},
+		},
+	}
+	relations := []types.ModelRelationType{
+		{SourceTable: "orders", TargetTable: "invoices", SourceField: "id", TargetField: "order_id", RelationType: ormRelations.OneToMany, OnDelete: ormActions.Restrict},
+		{SourceTable: "orders", TargetTable: "order_lines", SourceField: "id", TargetField: "order_id", RelationType: ormRelations.OneToMany, TargetModel: lineModel, OnDelete: ormActions.Cascade},
+		{SourceTable: "orders", TargetTable: "shipments", SourceField: "id", TargetField: "order_id", RelationType: ormRelations.OneToMany, OnDelete: ormActions.Null},
+		{SourceTable: "orders", TargetTable: "tags", SourceField: "id", TargetField: "id", ForeignField: "order_id", RelationType: ormRelations.ManyToMany, OnDelete: ormActions.Cascade},
+		{SourceTable: "customers", TargetTable: "orders", SourceField: "id", TargetField: "customer_id", RelationType: ormRelations.OneToMany, OnDelete: ormActions.Cascade},
+	}
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the restrict, cascade (descendants first), null and many-to-many action-queries:",
+		TestFunc: func() {
+			res, err := ComputeDeleteActionQueries("orders", relations, whereQuery, false)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, len(res), 5, "action-queries length should be: 5")
+			mctest.AssertEquals(t, res[0].ActionQuery, "SELECT EXISTS (SELECT 1 FROM invoices WHERE order_id IN (SELECT id FROM orders WHERE id = ANY($1)))", "restrict query should match")
+			mctest.AssertEquals(t, res[1].ActionQuery, "DELETE FROM line_notes WHERE line_id IN (SELECT id FROM order_lines WHERE order_id IN (SELECT id FROM orders WHERE id = ANY($1)))", "descendant cascade query should match")
+			mctest.AssertEquals(t, res[2].ActionQuery, "DELETE FROM order_lines WHERE order_id IN (SELECT id FROM orders WHERE id = ANY($1))", "child cascade query should match")
+			mctest.AssertEquals(t, res[3].ActionQuery, "UPDATE shipments SET order_id = NULL WHERE order_id IN (SELECT id FROM orders WHERE id = ANY($1))", "null query should match")
+			mctest.AssertEquals(t, res[4].ActionQuery, "DELETE FROM orders_tags WHERE order_id IN (SELECT id FROM orders WHERE id = ANY($1))", "many-to-many cascade query should match")
+			mctest.AssertEquals(t, res[4].ChildTable, "orders_tags", "many-to-many child-table should be: orders_tags")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should cascade the soft-delete to the soft-delete child-models only, and defer the null/default actions:",
+		TestFunc: func() {
+			softRelations := []types.ModelRelationType{
+				{SourceTable: "orders", TargetTable: "order_lines", SourceField: "id", TargetField: "order_id", TargetModel: types.ModelType{TableName: "order_lines", SoftDelete: true}, OnDelete: ormActions.Cascade},
+				{SourceTable: "orders", TargetTable: "payments", SourceField: "id", TargetField: "order_id", OnDelete: ormActions.Cascade},
+				{SourceTable: "orders", TargetTable: "shipments", SourceField: "id", TargetField: "order_id", OnDelete: ormActions.Default},
+			}
+			res, err := ComputeDeleteActionQueries("orders", softRelations, whereQuery, true)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, len(res), 1, "action-queries length should be: 1")
+			mctest.AssertEquals(t, res[0].ActionQuery, "UPDATE order_lines SET deleted_at=CURRENT_TIMESTAMP WHERE order_id IN (SELECT id FROM orders WHERE id = ANY($1)) AND deleted_at IS NULL", "soft-delete cascade query should match")
+			res, _ = ComputeDeleteActionQueries("orders", softRelations, whereQuery, false)
+			mctest.AssertEquals(t, res[2].ActionQuery, "UPDATE shipments SET order_id = DEFAULT WHERE order_id IN (SELECT id FROM orders WHERE id = ANY($1))", "default query should match")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return an error for the unknown OnDelete action:",
+		TestFunc: func() {
+			_, err := ComputeDeleteActionQueries("orders", []types.ModelRelationType{
+				{SourceTable: "orders", TargetTable: "invoices", SourceField: "id", TargetField: "order_id", OnDelete: "archive"},
+			}, whereQuery, false)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -0,0 +1,101 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-16 | @Updated: 2021-01-16
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute migration-history scripts, checksums and pending migrations
+
+package helper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"hash/fnv"
+	"sort"
+)
+
+// MigrationTable is the default migration-history table-name
+const MigrationTable = "mcorm_migrations"
+
+// ComputeMigrationTableQuery compose the create script of the migration-history table
+func ComputeMigrationTableQuery(tableName string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %v (version BIGINT PRIMARY KEY, name VARCHAR(255) NOT NULL, checksum VARCHAR(64) NOT NULL, applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP)", QuoteTableName(tableName))
+}
+
+// ComputeMigrationLockKey computes the advisory-lock key of the migration-history table
+func ComputeMigrationLockKey(tableName string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte("mcorm:" + tableName))
+	return int64(hash.Sum64())
+}
+
+// ComputeMigrationChecksum computes the sha256 checksum of the migration version, name and up/down scripts.
+// The Go-function migrations are checked by version and name only
+func ComputeMigrationChecksum(migration types.MigrationType) string {
+	checksum := sha256.Sum256([]byte(fmt.Sprintf("%v|%v|%v|%v", migration.Version, migration.Name, migration.UpSql, migration.DownSql)))
+	return hex.EncodeToString(checksum[:])
+}
+
+// SortMigrations validates and returns the migrations, ordered by version. The migration version must be
+// positive and unique, with an up-script or up-function
+func SortMigrations(migrations []types.MigrationType) ([]types.MigrationType, error) {
+	sortedMigrations := append([]types.MigrationType{}, migrations...)
+	sort.SliceStable(sortedMigrations, func(i, j int) bool {
+		return sortedMigrations[i].Version < sortedMigrations[j].Version
+	})
+	for i, migration := range sortedMigrations {
+		if migration.Version < 1 {
+			return nil, errors.New(fmt.Sprintf("migration [%v]: positive version is required", migration.Name))
+		}
+		if i > 0 && migration.Version == sortedMigrations[i-1].Version {
+			return nil, errors.New(fmt.Sprintf("migration [%v]: duplicate version: %v", migration.Name, migration.Version))
+		}
+		if migration.UpSql == "" && migration.Up == nil {
+			return nil, errors.New(fmt.Sprintf("migration [%v]: up-script or up-function is required", migration.Version))
+		}
+	}
+	return sortedMigrations, nil
+}
+
+// ComputePendingMigrations computes the pending (not applied) migrations, ordered by version, and validates the
+// checksums of the applied migrations, to reject the changed migrations
+func ComputePendingMigrations(migrations []types.MigrationType, history []types.MigrationHistoryType) ([]types.MigrationType, error) {
+	sortedMigrations, err := SortMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+	applied := map[int64]types.MigrationHistoryType{}
+	for _, item := range history {
+		applied[item.Version] = item
+	}
+	var pendingMigrations []types.MigrationType
+	for _, migration := range sortedMigrations {
+		item, ok := applied[migration.Version]
+		if !ok {
+			pendingMigrations = append(pendingMigrations, migration)
+			continue
+		}
+		if item.Checksum != ComputeMigrationChecksum(migration) {
+			return nil, errors.New(fmt.Sprintf("migration [%v - %v]: checksum mismatch, the applied migration has changed", migration.Version, migration.Name))
+		}
+	}
+	return pendingMigrations, nil
+}
+
+// ComputeOutOfOrderMigrations computes the pending migrations with a version lower than the last applied
+// migration version, i.e. to be applied out of order
+func ComputeOutOfOrderMigrations(pendingMigrations []types.MigrationType, history []types.MigrationHistoryType) []types.MigrationType {
+	var lastVersion int64
+	for _, item := range history {
+		if item.Version > lastVersion {
+			lastVersion = item.Version
+		}
+	}
+	var outOfOrderMigrations []types.MigrationType
+	for _, migration := range pendingMigrations {
+		if migration.Version < lastVersion {
+			outOfOrderMigrations = append(outOfOrderMigrations, migration)
+		}
+	}
+	return outOfOrderMigrations
+}
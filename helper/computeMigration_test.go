@@ -0,0 +1,80 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-16 | @Updated: 2021-01-16
+// @Company: mConnect.biz | @License: MIT
+// @Description: migration (pending migrations and checksums) test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+var migrations = []types.MigrationType{
+	{
+		Version: 2,
+		Name:    "add-users-email",
+		UpSql:   "ALTER TABLE users ADD COLUMN email VARCHAR(255)",
+		DownSql: "ALTER TABLE users DROP COLUMN email",
+	},
+	{
+		Version: 1,
+		Name:    "create-users",
+		UpSql:   "CREATE TABLE users (id UUID PRIMARY KEY)",
+		DownSql: "DROP TABLE users",
+	},
+}
+
+func TestComputePendingMigrations(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the pending migrations, ordered by version:",
+		TestFunc: func() {
+			history := []types.MigrationHistoryType{
+				{Version: 1, Name: "create-users", Checksum: ComputeMigrationChecksum(migrations[1])},
+			}
+			res, err := ComputePendingMigrations(migrations, history)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, len(res), 1, "pending migrations should be: 1")
+			mctest.AssertEquals(t, res[0].Version, int64(2), "pending migration version should be: 2")
+			res, err = ComputePendingMigrations(migrations, nil)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res[0].Version, int64(1), "first pending migration version should be: 1")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return error for the changed applied migration:",
+		TestFunc: func() {
+			history := []types.MigrationHistoryType{
+				{Version: 1, Name: "create-users", Checksum: ComputeMigrationChecksum(migrations[0])},
+			}
+			_, err := ComputePendingMigrations(migrations, history)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the out-of-order pending migrations, lower than the last applied version:",
+		TestFunc: func() {
+			history := []types.MigrationHistoryType{
+				{Version: 2, Name: "add-users-email", Checksum: ComputeMigrationChecksum(migrations[0])},
+			}
+			pendingMigrations, err := ComputePendingMigrations(migrations, history)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			res := ComputeOutOfOrderMigrations(pendingMigrations, history)
+			mctest.AssertEquals(t, len(res), 1, "out-of-order migrations should be: 1")
+			mctest.AssertEquals(t, res[0].Version, int64(1), "out-of-order migration version should be: 1")
+			mctest.AssertEquals(t, len(ComputeOutOfOrderMigrations(migrations, nil)), 0, "out-of-order migrations should be: 0, without history")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return error for duplicate versions and missing up-script:",
+		TestFunc: func() {
+			_, err := SortMigrations(append(migrations, types.MigrationType{Version: 2, Name: "duplicate", UpSql: "SELECT 1"}))
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			_, err = SortMigrations([]types.MigrationType{{Version: 3, Name: "no-up"}})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			mctest.AssertEquals(t, ComputeMigrationLockKey(MigrationTable), ComputeMigrationLockKey("mcorm_migrations"), "lock-key should be stable")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -0,0 +1,94 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-14 | @Updated: 2021-01-14
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute relation (eager-loading)-SQL script
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/ormRelations"
+	"github.com/asaskevich/govalidator"
+	"strconv"
+	"strings"
+)
+
+// RelationKeyField is the field-name of the relation-key (source-field-value) of the related records
+const RelationKeyField = "mcorm_relation_key"
+
+// ComputeRelationName returns the relation name, for eager-loading (include) and nesting of the related records
+func ComputeRelationName(relation types.ModelRelationType) string {
+	if relation.RelationName != "" {
+		return relation.RelationName
+	}
+	return relation.TargetTable
+}
+
+// ComputeRelationTable returns the many-to-many relation-table name | default: sourceTable_targetTable
+func ComputeRelationTable(relation types.ModelRelationType) string {
+	if relation.RelationTable != "" {
+		return relation.RelationTable
+	}
+	return relation.SourceTable + "_" + relation.TargetTable
+}
+
+// ComputeRelationKey returns the relation-key (string) of the source or target field-value, for matching/grouping
+// of the related records. Returns an empty key for nil values
+func ComputeRelationKey(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		// uuid-keys are case-insensitive
+		if lowerV := strings.ToLower(v); govalidator.IsUUID(lowerV) {
+			return lowerV
+		}
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// ComputeRelationQuery compose the (batched) select query for the related (target) records of the specified
+// source-field-values (relation-keys), with the relation-key as the first field (RelationKeyField).
+// one-to-one, one-to-many and many-to-one: target.targetField = source.sourceField.
+// many-to-many: relationTable.foreignField = source.sourceField and relationTable.relationField = target.targetField
+func ComputeRelationQuery(relation types.ModelRelationType, relationKeys []string) (types.SelectQueryResponseType, error) {
+	if relation.TargetTable == "" || !IsFieldName(relation.SourceField) || !IsFieldName(relation.TargetField) {
+		return types.SelectQueryResponseType{}, errors.New(fmt.Sprintf("target-table, valid source-field and target-field are required for the relation: %v", ComputeRelationName(relation)))
+	}
+	keysParam, err := ComputeIdsParam(relationKeys)
+	if err != nil {
+		return types.SelectQueryResponseType{}, errors.New(fmt.Sprintf("error computing relation-keys: %v", err.Error()))
+	}
+	var selectQuery, whereQuery string
+	switch strings.ToLower(relation.RelationType) {
+	case ormRelations.OneToOne, ormRelations.OneToMany, ormRelations.ManyToOne:
+		whereQuery = fmt.Sprintf("WHERE t.%v = ANY($1)", relation.TargetField)
+		selectQuery = fmt.Sprintf("SELECT t.%v AS %v, t.* FROM %v t %v", relation.TargetField, RelationKeyField, relation.TargetTable, whereQuery)
+	case ormRelations.ManyToMany:
+		if !IsFieldName(relation.ForeignField) || !IsFieldName(relation.RelationField) {
+			return types.SelectQueryResponseType{}, errors.New(fmt.Sprintf("valid foreign-field and relation-field are required for the many-to-many relation: %v", ComputeRelationName(relation)))
+		}
+		whereQuery = fmt.Sprintf("WHERE r.%v = ANY($1)", relation.ForeignField)
+		selectQuery = fmt.Sprintf("SELECT r.%v AS %v, t.* FROM %v t JOIN %v r ON r.%v = t.%v %v", relation.ForeignField, RelationKeyField, relation.TargetTable, ComputeRelationTable(relation), relation.RelationField, relation.TargetField, whereQuery)
+	default:
+		return types.SelectQueryResponseType{}, errors.New(fmt.Sprintf("Unknown or unsupported relation-type: %v", relation.RelationType))
+	}
+	// exclude the soft-deleted related records, of the soft-delete target-model
+	if relation.TargetModel.SoftDelete {
+		activeCondition := fmt.Sprintf(" AND t.%v IS NULL", ComputeSoftDeleteField(relation.TargetModel))
+		selectQuery += activeCondition
+		whereQuery += activeCondition
+	}
+	return types.SelectQueryResponseType{
+		SelectQuery: selectQuery,
+		WhereQuery:  whereQuery,
+		FieldValues: []interface{}{keysParam},
+	}, nil
+}
@@ -0,0 +1,75 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-14 | @Updated: 2021-01-14
+// @Company: mConnect.biz | @License: MIT
+// @Description: relation (eager-loading)-query test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/ormRelations"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+func TestComputeRelationQuery(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute one-to-many relation-query, by the relation-keys:",
+		TestFunc: func() {
+			relation := types.ModelRelationType{
+				SourceTable:  "users",
+				TargetTable:  "posts",
+				SourceField:  "id",
+				TargetField:  "user_id",
+				RelationType: ormRelations.OneToMany,
+			}
+			res, err := ComputeRelationQuery(relation, []string{"10", "20"})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.SelectQuery, "SELECT t.user_id AS mcorm_relation_key, t.* FROM posts t WHERE t.user_id = ANY($1)", "relation-query should match the relation")
+			mctest.AssertStrictEquals(t, res.FieldValues, []interface{}{[]string{"10", "20"}}, "relation-keys should be a single array-value")
+			mctest.AssertEquals(t, ComputeRelationName(relation), "posts", "relation-name should default to the target-table")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute many-to-many relation-query, through the relation-table:",
+		TestFunc: func() {
+			relation := types.ModelRelationType{
+				RelationName:  "roles",
+				SourceTable:   "users",
+				TargetTable:   "roles",
+				SourceField:   "id",
+				TargetField:   "id",
+				ForeignField:  "user_id",
+				RelationField: "role_id",
+				RelationType:  ormRelations.ManyToMany,
+			}
+			res, err := ComputeRelationQuery(relation, []string{"10"})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.SelectQuery, "SELECT r.user_id AS mcorm_relation_key, t.* FROM roles t JOIN users_roles r ON r.role_id = t.id WHERE r.user_id = ANY($1)", "relation-query should join the relation-table")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should exclude the soft-deleted related records, of the soft-delete target-model:",
+		TestFunc: func() {
+			relation := types.ModelRelationType{
+				SourceTable:  "users",
+				TargetTable:  "posts",
+				SourceField:  "id",
+				TargetField:  "user_id",
+				RelationType: ormRelations.OneToMany,
+				TargetModel:  types.ModelType{TableName: "posts", SoftDelete: true},
+			}
+			res, err := ComputeRelationQuery(relation, []string{"10"})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.SelectQuery, "SELECT t.user_id AS mcorm_relation_key, t.* FROM posts t WHERE t.user_id = ANY($1) AND t.deleted_at IS NULL", "relation-query should exclude the soft-deleted records")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the relation-keys, for matching source and target field-values:",
+		TestFunc: func() {
+			mctest.AssertEquals(t, ComputeRelationKey(float64(12)), ComputeRelationKey(int32(12)), "json-number and integer keys should match")
+			mctest.AssertEquals(t, ComputeRelationKey("6900D9F9-2CEB-450F-9A9E-527EB66C962F"), ComputeRelationKey(ComputeRowValue([16]uint8{0x69, 0x00, 0xd9, 0xf9, 0x2c, 0xeb, 0x45, 0x0f, 0x9a, 0x9e, 0x52, 0x7e, 0xb6, 0x6c, 0x96, 0x2f})), "uuid-string and uuid (row-value) keys should match")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -0,0 +1,43 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-21 | @Updated: 2021-01-21
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute the RETURNING (affected rows) scripts, of the create, update and delete queries
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ComputeReturningFields computes the returning table-fields, from the struct{} record-type (mcorm tags)
+func ComputeReturningFields(rec interface{}) ([]string, error) {
+	if !IsStructType(rec) {
+		return nil, errors.New("the return-record type must be a struct{} object")
+	}
+	returningFields, _, err := StructToFieldValues(rec, "mcorm")
+	if err != nil {
+		return nil, err
+	}
+	if len(returningFields) < 1 {
+		return nil, errors.New("the return-record type has no mcorm table-fields")
+	}
+	return returningFields, nil
+}
+
+// ComputeReturningQuery appends the returning-fields to the (create, update or delete) query: after the query's
+// RETURNING columns (e.g. RETURNING id), if any, otherwise as the RETURNING clause
+func ComputeReturningQuery(query string, returningFields []string) (string, error) {
+	if query == "" || len(returningFields) < 1 {
+		return "", errors.New("query and returning-fields are required")
+	}
+	for _, fieldName := range returningFields {
+		if !IsFieldName(fieldName) {
+			return "", errors.New(fmt.Sprintf("Invalid returning field-name: %v", fieldName))
+		}
+	}
+	if strings.Contains(query, " RETURNING ") {
+		return query + ", " + strings.Join(returningFields, ", "), nil
+	}
+	return query + " RETURNING " + strings.Join(returningFields, ", "), nil
+}
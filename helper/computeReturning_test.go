@@ -0,0 +1,44 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-21 | @Updated: 2021-01-21
+// @Company: mConnect.biz | @License: MIT
+// @Description: returning (affected rows) query test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+type returningRecord struct {
+	Id       string `mcorm:"id"`
+	Name     string `mcorm:"name"`
+	Priority int    `mcorm:"priority"`
+	Notes    string
+}
+
+func TestComputeReturningQuery(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the returning-fields, from the record-type mcorm tags:",
+		TestFunc: func() {
+			res, err := ComputeReturningFields(returningRecord{})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertStrictEquals(t, res, []string{"id", "name", "priority"}, "returning-fields should be: [id name priority]")
+			_, err = ComputeReturningFields("services")
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil, for the non-struct type")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the RETURNING clause, or append to the query's returning columns:",
+		TestFunc: func() {
+			res, err := ComputeReturningQuery("DELETE FROM services WHERE id = ANY($1)", []string{"id", "name"})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, "DELETE FROM services WHERE id = ANY($1) RETURNING id, name", "delete-query should match")
+			res, _ = ComputeReturningQuery("INSERT INTO services (name) VALUES ($1) RETURNING id", []string{"id", "name"})
+			mctest.AssertEquals(t, res, "INSERT INTO services (name) VALUES ($1) RETURNING id, id, name", "create-query should match")
+			_, err = ComputeReturningQuery("DELETE FROM services", []string{"name; DROP TABLE services"})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil, for the invalid field-name")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -22,39 +22,70 @@ func ComputeSelectQueryAll(tableName string, tableFields []string) (string, erro
 }
 
 // ComputeSelectQueryById compose select SQL script by id(s)
-func ComputeSelectQueryById(tableName string, recordIds []string, tableFields []string) (string, error) {
+func ComputeSelectQueryById(tableName string, recordIds []string, tableFields []string) (types.SelectQueryResponseType, error) {
 	if tableName == "" || len(recordIds) < 1 || len(tableFields) < 1 {
-		return "", errors.New("table-name, table-fields and record-ids are required to perform the select operation")
+		return types.SelectQueryResponseType{}, errors.New("table-name, table-fields and record-ids are required to perform the select operation")
 	}
 	// get record(s) based on projected/provided field names ([]string)
 	selectQuery := fmt.Sprintf("SELECT %v FROM %v ", strings.Join(tableFields, ", "), tableName)
-	// from / where condition (where-in-values)
-	whereIds := ""
-	idLen := len(recordIds)
-	for idCount, id := range recordIds {
-		whereIds += "'" + id + "'"
-		if idLen > 1 && idCount < idLen-1 {
-			whereIds += ", "
-		}
-	}
-	selectQuery += fmt.Sprintf("WHERE id IN( %v )", whereIds)
-	return selectQuery, nil
+	// from / where condition (id = ANY($1))
+	whereRes, err := ComputeWhereQueryByIds(recordIds, 0)
+	if err != nil {
+		return types.SelectQueryResponseType{}, errors.New(fmt.Sprintf("error computing where-query condition(s): %v", err.Error()))
+	}
+	return types.SelectQueryResponseType{
+		SelectQuery: selectQuery + whereRes.WhereQuery,
+		WhereQuery:  whereRes.WhereQuery,
+		FieldValues: whereRes.FieldValues,
+	}, nil
 }
 
 // ComputeSelectQueryByParam compose SELECT query from the where-parameters
-func ComputeSelectQueryByParam(tableName string, where types.QueryParamType, tableFields []string) (string, error) {
+func ComputeSelectQueryByParam(tableName string, where types.QueryParamType, tableFields []string) (types.SelectQueryResponseType, error) {
 	if tableName == "" || len(where) < 1 || len(tableFields) < 1 {
-		return "", errors.New("table-name, tableFields and where-params are required to perform the select operation")
+		return types.SelectQueryResponseType{}, errors.New("table-name, tableFields and where-params are required to perform the select operation")
 	}
 	// get record(s) based on projected/provided field names ([]string)
 	selectQuery := fmt.Sprintf("SELECT %v FROM %v ", strings.Join(tableFields, ", "), tableName)
 	// add where-params condition
-	if whereScript, err := ComputeWhereQuery(where); err == nil {
-		selectQuery += whereScript
-		return selectQuery, nil
+	if whereRes, err := ComputeWhereQuery(where, 0); err == nil {
+		return types.SelectQueryResponseType{
+			SelectQuery: selectQuery + whereRes.WhereQuery,
+			WhereQuery:  whereRes.WhereQuery,
+			FieldValues: whereRes.FieldValues,
+		}, nil
 	} else {
-		return "", errors.New(fmt.Sprintf("error computing where-query condition(s): %v", err.Error()))
+		return types.SelectQueryResponseType{}, errors.New(fmt.Sprintf("error computing where-query condition(s): %v", err.Error()))
+	}
+}
+
+// ComputeCountQuery compose SELECT COUNT query from the where-query (by record-ids or where-params),
+// or for all table-records, for an empty where-query
+func ComputeCountQuery(tableName string, whereRes types.WhereQueryResponseType) (types.SelectQueryResponseType, error) {
+	if tableName == "" {
+		return types.SelectQueryResponseType{}, errors.New("table-name is required to perform the count operation")
+	}
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %v", tableName)
+	if whereRes.WhereQuery != "" {
+		countQuery += " " + whereRes.WhereQuery
+	}
+	return types.SelectQueryResponseType{
+		SelectQuery: countQuery,
+		WhereQuery:  whereRes.WhereQuery,
+		FieldValues: whereRes.FieldValues,
+	}, nil
+}
+
+// ComputeExistsQuery compose SELECT EXISTS query from the where-query (by record-ids or where-params)
+func ComputeExistsQuery(tableName string, whereRes types.WhereQueryResponseType) (types.SelectQueryResponseType, error) {
+	if tableName == "" || whereRes.WhereQuery == "" {
+		return types.SelectQueryResponseType{}, errors.New("table-name and where-query are required to perform the exists operation")
 	}
+	return types.SelectQueryResponseType{
+		SelectQuery: fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %v %v)", tableName, whereRes.WhereQuery),
+		WhereQuery:  whereRes.WhereQuery,
+		FieldValues: whereRes.FieldValues,
+	}, nil
 }
 
-// TODO: select-query functions for relational tables (eager & lazy queries) and data aggregation
+// TODO: lazy-loading select-queries for the relational tables (eager-loading: see ComputeRelationQuery)
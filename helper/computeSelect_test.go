@@ -0,0 +1,45 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-12 | @Updated: 2021-01-12
+// @Company: mConnect.biz | @License: MIT
+// @Description: select, count and exists-query test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+func TestComputeCountQuery(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute count-query by where-params:",
+		TestFunc: func() {
+			whereRes, _ := ComputeWhereQuery(whereParams, 0)
+			res, err := ComputeCountQuery("users", whereRes)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.SelectQuery, "SELECT COUNT(*) FROM users "+whereRes.WhereQuery, "count-query should include the where-query")
+			mctest.AssertEquals(t, len(res.FieldValues), len(whereRes.FieldValues), "field-values should be the where-query field-values")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute count-query for all records:",
+		TestFunc: func() {
+			res, err := ComputeCountQuery("users", types.WhereQueryResponseType{})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.SelectQuery, "SELECT COUNT(*) FROM users", "count-query should be without a where-query")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute exists-query by record-ids, and require a where-query:",
+		TestFunc: func() {
+			whereRes, _ := ComputeWhereQueryByIds([]string{"10", "20"}, 0)
+			res, err := ComputeExistsQuery("users", whereRes)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.SelectQuery, "SELECT EXISTS (SELECT 1 FROM users WHERE id = ANY($1))", "exists-query should match the where-query")
+			_, err = ComputeExistsQuery("users", types.WhereQueryResponseType{})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -0,0 +1,138 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-21 | @Updated: 2021-01-21
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute soft-delete (deletedAt) delete, restore, purge and read-filter SQL scripts
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"strings"
+	"time"
+)
+
+// ComputeSoftDeleteField computes the soft-delete (table) field of the model: deleted_at, for the SoftDelete
+// model | empty, if not enabled
+func ComputeSoftDeleteField(model types.ModelType) string {
+	if model.SoftDelete {
+		return "deleted_at"
+	}
+	return ""
+}
+
+// ComputeSoftDeleteWhere combines the where-query with the soft-deleted (deleted: IS NOT NULL) or the active
+// (IS NULL) records condition
+func ComputeSoftDeleteWhere(whereRes types.WhereQueryResponseType, softDeleteField string, deleted bool) (types.WhereQueryResponseType, error) {
+	if !IsFieldName(softDeleteField) {
+		return types.WhereQueryResponseType{}, errors.New(fmt.Sprintf("Invalid soft-delete field-name: %v", softDeleteField))
+	}
+	deleteCondition := fmt.Sprintf("%v IS NULL", softDeleteField)
+	if deleted {
+		deleteCondition = fmt.Sprintf("%v IS NOT NULL", softDeleteField)
+	}
+	whereQuery := "WHERE " + deleteCondition
+	if whereRes.WhereQuery != "" {
+		whereQuery = fmt.Sprintf("WHERE (%v) AND %v", strings.TrimPrefix(whereRes.WhereQuery, "WHERE "), deleteCondition)
+	}
+	return types.WhereQueryResponseType{
+		WhereQuery:  whereQuery,
+		FieldValues: whereRes.FieldValues,
+	}, nil
+}
+
+// ComputeSoftDeleteSelect excludes the soft-deleted records from the select-query
+func ComputeSoftDeleteSelect(selectQuery types.SelectQueryResponseType, softDeleteField string) (types.SelectQueryResponseType, error) {
+	whereRes, err := ComputeSoftDeleteWhere(types.WhereQueryResponseType{
+		WhereQuery:  selectQuery.WhereQuery,
+		FieldValues: selectQuery.FieldValues,
+	}, softDeleteField, false)
+	if err != nil {
+		return types.SelectQueryResponseType{}, err
+	}
+	selectScript := strings.TrimSpace(strings.TrimSuffix(selectQuery.SelectQuery, selectQuery.WhereQuery))
+	return types.SelectQueryResponseType{
+		SelectQuery: selectScript + " " + whereRes.WhereQuery,
+		WhereQuery:  whereRes.WhereQuery,
+		FieldValues: whereRes.FieldValues,
+	}, nil
+}
+
+// ComputeSoftDeleteUpdate excludes the soft-deleted records from the update-query
+func ComputeSoftDeleteUpdate(updateQuery types.UpdateQueryResponseType, softDeleteField string) (types.UpdateQueryResponseType, error) {
+	whereIndex := strings.LastIndex(updateQuery.UpdateQuery, updateQuery.WhereQuery)
+	if updateQuery.WhereQuery == "" || whereIndex < 0 {
+		return types.UpdateQueryResponseType{}, errors.New("where-query is required to exclude the soft-deleted records")
+	}
+	whereRes, err := ComputeSoftDeleteWhere(types.WhereQueryResponseType{WhereQuery: updateQuery.WhereQuery}, softDeleteField, false)
+	if err != nil {
+		return types.UpdateQueryResponseType{}, err
+	}
+	// the where-query may be followed by the returning-script, e.g. RETURNING id
+	updateQuery.UpdateQuery = updateQuery.UpdateQuery[:whereIndex] + whereRes.WhereQuery + updateQuery.UpdateQuery[whereIndex+len(updateQuery.WhereQuery):]
+	updateQuery.WhereQuery = whereRes.WhereQuery
+	return updateQuery, nil
+}
+
+// ComputeSoftDeleteQuery computes the soft-delete script, setting the soft-delete field of the active records
+// that met the where-query
+func ComputeSoftDeleteQuery(tableName string, whereRes types.WhereQueryResponseType, softDeleteField string) (types.DeleteQueryResponseType, error) {
+	if tableName == "" {
+		return types.DeleteQueryResponseType{}, errors.New("table-name is required for the soft-delete operation")
+	}
+	softWhereRes, err := ComputeSoftDeleteWhere(whereRes, softDeleteField, false)
+	if err != nil {
+		return types.DeleteQueryResponseType{}, err
+	}
+	return types.DeleteQueryResponseType{
+		DeleteQuery: fmt.Sprintf("UPDATE %v SET %v=CURRENT_TIMESTAMP %v", tableName, softDeleteField, softWhereRes.WhereQuery),
+		WhereQuery:  softWhereRes.WhereQuery,
+		FieldValues: softWhereRes.FieldValues,
+	}, nil
+}
+
+// ComputeRestoreQuery computes the restore script, clearing the soft-delete field of the soft-deleted records
+// that met the where-query
+func ComputeRestoreQuery(tableName string, whereRes types.WhereQueryResponseType, softDeleteField string) (types.DeleteQueryResponseType, error) {
+	if tableName == "" {
+		return types.DeleteQueryResponseType{}, errors.New("table-name is required for the restore operation")
+	}
+	softWhereRes, err := ComputeSoftDeleteWhere(whereRes, softDeleteField, true)
+	if err != nil {
+		return types.DeleteQueryResponseType{}, err
+	}
+	return types.DeleteQueryResponseType{
+		DeleteQuery: fmt.Sprintf("UPDATE %v SET %v=NULL %v", tableName, softDeleteField, softWhereRes.WhereQuery),
+		WhereQuery:  softWhereRes.WhereQuery,
+		FieldValues: softWhereRes.FieldValues,
+	}, nil
+}
+
+// ComputePurgeQuery computes the purge (permanent delete) script, of the soft-deleted records that met the
+// where-query
+func ComputePurgeQuery(tableName string, whereRes types.WhereQueryResponseType, softDeleteField string) (types.DeleteQueryResponseType, error) {
+	if tableName == "" {
+		return types.DeleteQueryResponseType{}, errors.New("table-name is required for the purge operation")
+	}
+	softWhereRes, err := ComputeSoftDeleteWhere(whereRes, softDeleteField, true)
+	if err != nil {
+		return types.DeleteQueryResponseType{}, err
+	}
+	return types.DeleteQueryResponseType{
+		DeleteQuery: fmt.Sprintf("DELETE FROM %v %v", tableName, softWhereRes.WhereQuery),
+		WhereQuery:  softWhereRes.WhereQuery,
+		FieldValues: softWhereRes.FieldValues,
+	}, nil
+}
+
+// ComputePurgeBeforeQuery computes the purge (permanent delete) script, of the records soft-deleted before the
+// deletedBefore time, e.g. after the retention period
+func ComputePurgeBeforeQuery(tableName string, softDeleteField string, deletedBefore time.Time) (types.DeleteQueryResponseType, error) {
+	if tableName == "" || deletedBefore.IsZero() {
+		return types.DeleteQueryResponseType{}, errors.New("table-name and deleted-before time are required for the purge operation")
+	}
+	return ComputePurgeQuery(tableName, types.WhereQueryResponseType{
+		WhereQuery:  fmt.Sprintf("WHERE %v < $1", softDeleteField),
+		FieldValues: []interface{}{deletedBefore},
+	}, softDeleteField)
+}
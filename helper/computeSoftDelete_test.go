@@ -0,0 +1,85 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-21 | @Updated: 2021-01-21
+// @Company: mConnect.biz | @License: MIT
+// @Description: soft-delete (deletedAt) query test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"github.com/abbeymart/mctest"
+	"testing"
+	"time"
+)
+
+func TestComputeSoftDeleteQuery(t *testing.T) {
+	recordIds := []string{"6900d9f9-2ceb-450f-9a9e-527eb66c962f"}
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the soft-delete, restore and purge queries, by record-ids:",
+		TestFunc: func() {
+			whereRes, _ := ComputeWhereQueryByIds(recordIds, 0)
+			res, err := ComputeSoftDeleteQuery("services", whereRes, "deleted_at")
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.DeleteQuery, "UPDATE services SET deleted_at=CURRENT_TIMESTAMP WHERE (id = ANY($1)) AND deleted_at IS NULL", "soft-delete query should match")
+			res, _ = ComputeRestoreQuery("services", whereRes, "deleted_at")
+			mctest.AssertEquals(t, res.DeleteQuery, "UPDATE services SET deleted_at=NULL WHERE (id = ANY($1)) AND deleted_at IS NOT NULL", "restore query should match")
+			res, _ = ComputePurgeQuery("services", whereRes, "deleted_at")
+			mctest.AssertEquals(t, res.DeleteQuery, "DELETE FROM services WHERE (id = ANY($1)) AND deleted_at IS NOT NULL", "purge query should match")
+			mctest.AssertEquals(t, len(res.FieldValues), 1, "field-values length should be: 1")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the retention purge query, of the records deleted before the time:",
+		TestFunc: func() {
+			deletedBefore := time.Date(2020, 1, 21, 0, 0, 0, 0, time.UTC)
+			res, err := ComputePurgeBeforeQuery("services", "deleted_at", deletedBefore)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.DeleteQuery, "DELETE FROM services WHERE (deleted_at < $1) AND deleted_at IS NOT NULL", "purge query should match")
+			mctest.AssertStrictEquals(t, res.FieldValues, []interface{}{deletedBefore}, "field-values should be: [deletedBefore]")
+			_, err = ComputePurgeBeforeQuery("services", "deleted_at", time.Time{})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil, for the zero time")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should exclude the soft-deleted records from the select-queries:",
+		TestFunc: func() {
+			selectQuery, _ := ComputeSelectQueryById("services", recordIds, []string{"id", "name"})
+			res, err := ComputeSoftDeleteSelect(selectQuery, "deleted_at")
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.SelectQuery, "SELECT id, name FROM services WHERE (id = ANY($1)) AND deleted_at IS NULL", "select-query should match")
+			selectAllQuery, _ := ComputeSelectQueryAll("services", []string{"id", "name"})
+			res, _ = ComputeSoftDeleteSelect(types.SelectQueryResponseType{SelectQuery: selectAllQuery}, "deleted_at")
+			mctest.AssertEquals(t, res.SelectQuery, "SELECT id, name FROM services WHERE deleted_at IS NULL", "select-all query should match")
+			mctest.AssertEquals(t, res.WhereQuery, "WHERE deleted_at IS NULL", "where-query should match")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should exclude the soft-deleted records from the update-queries:",
+		TestFunc: func() {
+			updateQuery, _ := ComputeUpdateQuery("services", types.ActionParamsType{{"id": recordIds[0], "name": "Cleaning"}}, []string{"id", "name"})
+			res, err := ComputeSoftDeleteUpdate(updateQuery, "deleted_at")
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.UpdateQuery, "UPDATE services SET name=$1 WHERE (id=$2) AND deleted_at IS NULL", "update-query should match")
+			versionQuery, _ := ComputeVersionUpdateQueryById("services", types.ActionParamsType{{"name": "Cleaning", "version": 2}}, recordIds, []string{"name"}, "version")
+			res, _ = ComputeSoftDeleteUpdate(versionQuery, "deleted_at")
+			mctest.AssertEquals(t, res.UpdateQuery, "UPDATE services SET name=$1, version=version+1 WHERE (id = ANY($2) AND version=$3) AND deleted_at IS NULL RETURNING id", "version-update query should match")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the soft-delete field and add deletedAt to the table-fields, for the soft-delete model:",
+		TestFunc: func() {
+			model := types.ModelType{
+				TableName:  "services",
+				RecordDesc: types.RecordDescType{"name": types.FieldDescType{FieldType: datatypes.String}},
+				SoftDelete: true,
+			}
+			mctest.AssertEquals(t, ComputeSoftDeleteField(model), "deleted_at", "soft-delete field should be: deleted_at")
+			mctest.AssertEquals(t, ComputeSoftDeleteField(types.ModelType{}), "", "soft-delete field should be: empty")
+			modelFields, err := ComputeModelFields(model)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, modelFields[len(modelFields)-1].FieldName, "deleted_at", "last table-field should be: deleted_at")
+		},
+	})
+
+	mctest.PostTestResult()
+}
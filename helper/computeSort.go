@@ -0,0 +1,68 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-09 | @Updated: 2021-01-09
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute order-by-SQL script
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"strings"
+)
+
+// ComputeSortQuery function computes the ORDER BY script from the sort-params, in order of sort-priority.
+// The field-names are validated against the model record-description, and the camelCase field-names are converted
+// to the snake_case column-names. The id field is appended as the final tie-breaker, for deterministic ordering.
+// Returns an empty script for empty sort-params
+func ComputeSortQuery(sortParams types.SortParamType, recordDesc types.RecordDescType) (string, error) {
+	if len(sortParams) < 1 {
+		return "", nil
+	}
+	var sortScripts []string
+	for _, item := range ComputeSortItems(sortParams) {
+		if !IsModelField(item.FieldName, recordDesc) {
+			return "", errors.New(fmt.Sprintf("Invalid or unknown sort field-name: %v", item.FieldName))
+		}
+		var sortOrder string
+		switch item.SortOrder {
+		case 1:
+			sortOrder = "ASC"
+		case -1:
+			sortOrder = "DESC"
+		default:
+			return "", errors.New(fmt.Sprintf("Invalid sort-order [%v] for field-name: %v, expected 1 (asc) or -1 (desc)", item.SortOrder, item.FieldName))
+		}
+		sortScript := fmt.Sprintf("%v %v", item.FieldName, sortOrder)
+		switch strings.ToLower(item.NullsOrder) {
+		case "":
+			break
+		case "first":
+			sortScript += " NULLS FIRST"
+		case "last":
+			sortScript += " NULLS LAST"
+		default:
+			return "", errors.New(fmt.Sprintf("Invalid nulls-order [%v] for field-name: %v, expected first or last", item.NullsOrder, item.FieldName))
+		}
+		sortScripts = append(sortScripts, sortScript)
+	}
+	return "ORDER BY " + strings.Join(sortScripts, ", "), nil
+}
+
+// ComputeSortItems function returns the sort-params, with the snake_case (column) field-names and the id field (asc)
+// appended as the final tie-breaker, if not already specified
+func ComputeSortItems(sortParams types.SortParamType) types.SortParamType {
+	var sortItems types.SortParamType
+	hasId := false
+	for _, item := range sortParams {
+		item.FieldName = ToSnakeCase(item.FieldName)
+		if item.FieldName == "id" {
+			hasId = true
+		}
+		sortItems = append(sortItems, item)
+	}
+	if hasId {
+		return sortItems
+	}
+	return append(sortItems, types.SortItemType{FieldName: "id", SortOrder: 1})
+}
@@ -0,0 +1,70 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-09 | @Updated: 2021-01-09
+// @Company: mConnect.biz | @License: MIT
+// @Description: sort/order-by-query test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+var sortRecordDesc = types.RecordDescType{
+	"name": {
+		FieldType: datatypes.String,
+	},
+	"age": {
+		FieldType: datatypes.Integer,
+	},
+}
+
+func TestComputeSortQuery(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute order-by-query, in order of sort-priority, with id as the tie-breaker:",
+		TestFunc: func() {
+			sortParams := types.SortParamType{
+				{FieldName: "age", SortOrder: -1, NullsOrder: "last"},
+				{FieldName: "name", SortOrder: 1},
+				{FieldName: "created_at", SortOrder: -1, NullsOrder: "First"},
+			}
+			res, err := ComputeSortQuery(sortParams, sortRecordDesc)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, "ORDER BY age DESC NULLS LAST, name ASC, created_at DESC NULLS FIRST, id ASC", "order-by-query should match the sort-params")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute order-by-query with the snake_case column-names, for the camelCase field-names:",
+		TestFunc: func() {
+			sortParams := types.SortParamType{
+				{FieldName: "createdAt", SortOrder: -1},
+				{FieldName: "isActive", SortOrder: 1},
+			}
+			res, err := ComputeSortQuery(sortParams, sortRecordDesc)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, "ORDER BY created_at DESC, is_active ASC, id ASC", "order-by-query should match the sort-params")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return an empty order-by-query for empty sort-params:",
+		TestFunc: func() {
+			res, err := ComputeSortQuery(types.SortParamType{}, sortRecordDesc)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, "", "order-by-query should be empty")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return an error for unknown field-name or invalid sort-order:",
+		TestFunc: func() {
+			_, err := ComputeSortQuery(types.SortParamType{{FieldName: "salary", SortOrder: 1}}, sortRecordDesc)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			_, err = ComputeSortQuery(types.SortParamType{{FieldName: "name", SortOrder: 0}}, sortRecordDesc)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			_, err = ComputeSortQuery(types.SortParamType{{FieldName: "name", SortOrder: 1, NullsOrder: "middle"}}, sortRecordDesc)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -0,0 +1,241 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-18 | @Updated: 2021-01-18
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute the model (record-description) from the tagged struct
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// ComputeGoFieldType computes the data-type of the struct-field Go type, e.g. string => datatypes.String
+func ComputeGoFieldType(fieldType reflect.Type) (string, error) {
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType == timeType {
+		return datatypes.DateTime, nil
+	}
+	switch fieldType.Kind() {
+	case reflect.String:
+		return datatypes.String, nil
+	case reflect.Bool:
+		return datatypes.Boolean, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32:
+		return datatypes.Integer, nil
+	case reflect.Int64, reflect.Uint64:
+		return datatypes.BigInt, nil
+	case reflect.Float32:
+		return datatypes.Float32, nil
+	case reflect.Float64:
+		return datatypes.Float64, nil
+	case reflect.Map:
+		return datatypes.Map, nil
+	case reflect.Struct:
+		return datatypes.Object, nil
+	case reflect.Slice, reflect.Array:
+		elemType, err := ComputeGoFieldType(fieldType.Elem())
+		if err != nil {
+			return datatypes.Array, nil
+		}
+		switch elemType {
+		case datatypes.String:
+			return datatypes.ArrayOfString, nil
+		case datatypes.Integer, datatypes.BigInt, datatypes.Float32, datatypes.Float64:
+			return datatypes.ArrayOfNumber, nil
+		case datatypes.Boolean:
+			return datatypes.ArrayOfBoolean, nil
+		case datatypes.Object:
+			return datatypes.ArrayOfStruct, nil
+		case datatypes.Map:
+			return datatypes.ArrayOfMap, nil
+		case datatypes.ArrayOfString, datatypes.ArrayOfNumber, datatypes.ArrayOfBoolean, datatypes.ArrayOfStruct,
+			datatypes.ArrayOfMap, datatypes.ArrayOfArray, datatypes.Array:
+			return datatypes.ArrayOfArray, nil
+		default:
+			return datatypes.Array, nil
+		}
+	default:
+		return "", errors.New(fmt.Sprintf("Unsupported struct-field type: %v", fieldType))
+	}
+}
+
+// ComputeTagDefaultValue computes the default-value (function) of the default tag-option, by the data-type.
+// The default "now" is the current time, for the date/time data-types
+func ComputeTagDefaultValue(fieldType string, value string) (types.DefaultValueType, error) {
+	var defaultValue interface{}
+	var err error
+	switch fieldType {
+	case datatypes.Integer, datatypes.Positive, datatypes.Natural, datatypes.Negative, datatypes.Port:
+		defaultValue, err = strconv.Atoi(value)
+	case datatypes.BigInt:
+		defaultValue, err = strconv.ParseInt(value, 10, 64)
+	case datatypes.Number, datatypes.Decimal, datatypes.Float, datatypes.Float32, datatypes.Float64,
+		datatypes.BigFloat, datatypes.Latitude, datatypes.Longitude:
+		defaultValue, err = strconv.ParseFloat(value, 64)
+	case datatypes.Boolean:
+		defaultValue, err = strconv.ParseBool(value)
+	case datatypes.DateTime, datatypes.Date, datatypes.Time, datatypes.TimeStamp, datatypes.TimeStampZ:
+		if value != "now" {
+			return nil, errors.New(fmt.Sprintf("Unsupported date/time default-value: %v | expected: now", value))
+		}
+		return func() interface{} { return time.Now() }, nil
+	default:
+		defaultValue = value
+	}
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Invalid %v default-value: %v", fieldType, value))
+	}
+	return func() interface{} { return defaultValue }, nil
+}
+
+// ComputeTagFieldDesc computes the table-field name and the field-description of the struct-field mcorm tag:
+// name[,type=email][,len=120][,notnull][,unique][,index][,pk][,min=1][,max=10][,pattern=^[0-9]+$][,default=value]
+// [,former=oldName]. The data-type defaults to the Go type of the struct-field, and the field allows null
+// values, unless notnull or pk is specified. The pattern may not include commas
+func ComputeTagFieldDesc(field reflect.StructField, tagValue string) (string, types.FieldDescType, error) {
+	tagItems := strings.Split(tagValue, ",")
+	fieldName := strings.TrimSpace(tagItems[0])
+	if !IsFieldName(fieldName) {
+		return "", types.FieldDescType{}, errors.New(fmt.Sprintf("Invalid tag field-name [%v]: %v", field.Name, fieldName))
+	}
+	fieldDesc := types.FieldDescType{AllowNull: true}
+	var defaultValue *string
+	for _, tagItem := range tagItems[1:] {
+		option, value := strings.TrimSpace(tagItem), ""
+		if index := strings.Index(option, "="); index >= 0 {
+			option, value = option[:index], option[index+1:]
+		}
+		var err error
+		switch option {
+		case "type":
+			fieldDesc.FieldType = value
+		case "len":
+			fieldDesc.FieldLength, err = strconv.Atoi(value)
+		case "notnull":
+			fieldDesc.AllowNull = false
+		case "unique":
+			fieldDesc.Unique = true
+		case "index":
+			fieldDesc.Indexable = true
+		case "pk":
+			fieldDesc.PrimaryKey = true
+			fieldDesc.AllowNull = false
+		case "min":
+			fieldDesc.MinValue, err = strconv.Atoi(value)
+		case "max":
+			fieldDesc.MaxValue, err = strconv.Atoi(value)
+		case "pattern":
+			fieldDesc.FieldPattern = value
+		case "default":
+			defaultValue = &value
+		case "former":
+			fieldDesc.FormerName = value
+		case "":
+			continue
+		default:
+			return "", types.FieldDescType{}, errors.New(fmt.Sprintf("Unknown tag-option [%v]: %v", field.Name, option))
+		}
+		if err != nil {
+			return "", types.FieldDescType{}, errors.New(fmt.Sprintf("Invalid tag-option [%v]: %v=%v", field.Name, option, value))
+		}
+	}
+	if fieldDesc.FieldType == "" {
+		fieldType, err := ComputeGoFieldType(field.Type)
+		if err != nil {
+			return "", types.FieldDescType{}, errors.New(fmt.Sprintf("struct-field [%v]: %v", field.Name, err.Error()))
+		}
+		fieldDesc.FieldType = fieldType
+	} else if _, err := ComputeFieldType(fieldDesc); err != nil {
+		return "", types.FieldDescType{}, errors.New(fmt.Sprintf("struct-field [%v]: %v", field.Name, err.Error()))
+	}
+	if defaultValue != nil {
+		defaultFunc, err := ComputeTagDefaultValue(fieldDesc.FieldType, *defaultValue)
+		if err != nil {
+			return "", types.FieldDescType{}, errors.New(fmt.Sprintf("struct-field [%v]: %v", field.Name, err.Error()))
+		}
+		fieldDesc.DefaultValue = defaultFunc
+	}
+	return fieldName, fieldDesc, nil
+}
+
+// computeStructRecordDesc computes the record-description of the struct-type fields, including the fields of the
+// embedded (untagged) structs
+func computeStructRecordDesc(recType reflect.Type, tag string, recordDesc types.RecordDescType) error {
+	for i := 0; i < recType.NumField(); i++ {
+		field := recType.Field(i)
+		tagValue := field.Tag.Get(tag)
+		if field.Anonymous && tagValue == "" {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				if err := computeStructRecordDesc(embeddedType, tag, recordDesc); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if field.PkgPath != "" || tagValue == "" || tagValue == "-" {
+			continue
+		}
+		fieldName, fieldDesc, err := ComputeTagFieldDesc(field, tagValue)
+		if err != nil {
+			return err
+		}
+		key := ToCamelCase(fieldName, false)
+		if _, ok := recordDesc[key]; ok {
+			return errors.New(fmt.Sprintf("Duplicate tag field-name: %v", fieldName))
+		}
+		recordDesc[key] = fieldDesc
+	}
+	return nil
+}
+
+// StructToRecordDesc computes the model record-description (by camelCase field-name) from the mcorm tags of the
+// struct (or pointer to struct) record
+func StructToRecordDesc(rec interface{}) (types.RecordDescType, error) {
+	recType := reflect.TypeOf(rec)
+	if recType != nil && recType.Kind() == reflect.Ptr {
+		recType = recType.Elem()
+	}
+	if recType == nil || recType.Kind() != reflect.Struct {
+		return nil, errors.New("invalid type - requires parameter of type struct only")
+	}
+	recordDesc := types.RecordDescType{}
+	if err := computeStructRecordDesc(recType, "mcorm", recordDesc); err != nil {
+		return nil, err
+	}
+	if len(recordDesc) < 1 {
+		return nil, errors.New("no mcorm tag-fields found for the struct")
+	}
+	return recordDesc, nil
+}
+
+// StructToModel computes the model of the tagged struct record, for the table-name | default: snake_case
+// struct-name, e.g. UserRole => user_role
+func StructToModel(rec interface{}, tableName string) (types.ModelType, error) {
+	recordDesc, err := StructToRecordDesc(rec)
+	if err != nil {
+		return types.ModelType{}, err
+	}
+	if tableName == "" {
+		recType := reflect.TypeOf(rec)
+		if recType.Kind() == reflect.Ptr {
+			recType = recType.Elem()
+		}
+		tableName = ToSnakeCase(recType.Name())
+	}
+	return types.ModelType{TableName: tableName, RecordDesc: recordDesc}, nil
+}
@@ -0,0 +1,83 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-18 | @Updated: 2021-01-18
+// @Company: mConnect.biz | @License: MIT
+// @Description: struct-to-model (mcorm tags) test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"github.com/abbeymart/mctest"
+	"testing"
+	"time"
+)
+
+type StampFields struct {
+	CreatedAt time.Time `json:"createdAt" mcorm:"created_at,default=now,notnull"`
+}
+
+type UserAccount struct {
+	Id       string   `json:"id" mcorm:"id,type=uuid,pk"`
+	Email    string   `json:"email" mcorm:"email,type=email,len=120,notnull,unique,index"`
+	Age      int      `json:"age" mcorm:"age,min=18,max=120"`
+	Active   bool     `json:"active" mcorm:"active,notnull,default=true"`
+	Tags     []string `json:"tags" mcorm:"tags"`
+	Password string   `json:"-" mcorm:"-"`
+	StampFields
+}
+
+func TestStructToModel(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the model record-description, from the struct mcorm tags:",
+		TestFunc: func() {
+			model, err := StructToModel(&UserAccount{}, "")
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, model.TableName, "user_account", "table-name should be the snake_case struct-name")
+			mctest.AssertEquals(t, len(model.RecordDesc), 6, "record-description should include the tagged and embedded fields")
+			email := model.RecordDesc["email"]
+			mctest.AssertEquals(t, email.FieldType, datatypes.Email, "email field-type should be: email")
+			mctest.AssertEquals(t, email.FieldLength, 120, "email field-length should be: 120")
+			mctest.AssertEquals(t, email.AllowNull, false, "email should be not-null")
+			mctest.AssertEquals(t, email.Unique && email.Indexable, true, "email should be unique and indexable")
+			mctest.AssertEquals(t, model.RecordDesc["age"].FieldType, datatypes.Integer, "age field-type should be the Go type: integer")
+			mctest.AssertEquals(t, model.RecordDesc["age"].MaxValue, 120, "age max-value should be: 120")
+			mctest.AssertEquals(t, model.RecordDesc["tags"].FieldType, datatypes.ArrayOfString, "tags field-type should be: arrayofstring")
+			mctest.AssertEquals(t, model.RecordDesc["active"].DefaultValue(), true, "active default-value should be: true")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the create-table script of the struct model:",
+		TestFunc: func() {
+			model, _ := StructToModel(UserAccount{}, "accounts")
+			res, err := CreateTableQuery(model)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, `CREATE TABLE IF NOT EXISTS "accounts" (
+	"id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	"active" BOOLEAN NOT NULL DEFAULT TRUE,
+	"age" INTEGER,
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	"email" VARCHAR(120) NOT NULL UNIQUE,
+	"tags" TEXT[]
+);`, "create-table script should match the struct model")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return error for unknown tag-option, data-type and invalid default-value:",
+		TestFunc: func() {
+			type invalidOption struct {
+				Name string `mcorm:"name,size=10"`
+			}
+			type invalidType struct {
+				Name string `mcorm:"name,type=varchar"`
+			}
+			type invalidDefault struct {
+				Count int `mcorm:"count,default=ten"`
+			}
+			for _, rec := range []interface{}{invalidOption{}, invalidType{}, invalidDefault{}} {
+				_, err := StructToModel(rec, "")
+				mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			}
+		},
+	})
+
+	mctest.PostTestResult()
+}
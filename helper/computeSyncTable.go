@@ -1,22 +1,258 @@
-// @Author: abbeymart | Abi Akindele | @Created: 2020-12-08 | @Updated: 2020-12-08
+// @Author: abbeymart | Abi Akindele | @Created: 2020-12-08 | @Updated: 2021-01-15
 // @Company: mConnect.biz | @License: MIT
 // @Description: compute sync-table script / data migration actions
 
 package helper
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/abbeymart/mcorm/types"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"sort"
+	"strings"
 )
 
-func SyncTableQuery(model types.ModelType, appDb *pgxpool.Pool) (string, error)  {
+// ComputeCopyTableQuery compose the copy script of the existing records into the temporary-table, converted
+// into the new shape: the model fields with an existing (or former-name) table column are copied and retyped, as
+// required, the new NOT NULL fields are set to their default-values or the column-type fill-value (e.g. an empty
+// string or 0), and the other new fields are set by the table defaults
+func ComputeCopyTableQuery(model types.ModelType, tableColumns map[string]TableColumnType) (string, error) {
+	modelFields, err := ComputeModelFields(model)
+	if err != nil {
+		return "", err
+	}
+	primaryFields := ComputePrimaryFields(modelFields)
+	var fieldNames, selectFields []string
+	for _, field := range modelFields {
+		column, ok := tableColumns[field.FieldName]
+		if !ok && field.FieldDesc.FormerName != "" {
+			column, ok = tableColumns[ToSnakeCase(field.FieldDesc.FormerName)]
+		}
+		fieldType, err := ComputeFieldType(field.FieldDesc)
+		if err != nil {
+			return "", errors.New(fmt.Sprintf("field [%v]: %v", field.FieldName, err.Error()))
+		}
+		if !ok {
+			// new NOT NULL field: the default-value or the fill-value of the existing records
+			if field.FieldDesc.AllowNull || ArrayStringContains(primaryFields, field.FieldName) {
+				continue
+			}
+			fillValue, err := ComputeDefaultValue(field.FieldDesc)
+			if err != nil {
+				return "", errors.New(fmt.Sprintf("field [%v]: %v", field.FieldName, err.Error()))
+			}
+			if fillValue == "" {
+				fillValue = computeFillValue(fieldType)
+			}
+			if fillValue == "" {
+				return "", errors.New(fmt.Sprintf("field [%v]: a default-value is required to copy the records into the NOT NULL column", field.FieldName))
+			}
+			fieldNames = append(fieldNames, pgx.Identifier{field.FieldName}.Sanitize())
+			selectFields = append(selectFields, fillValue)
+			continue
+		}
+		selectField := pgx.Identifier{column.ColumnName}.Sanitize()
+		if isFieldTypeChanged(fieldType, column) {
+			selectField = fmt.Sprintf("CAST(%v AS %v)", selectField, fieldType)
+		}
+		fieldNames = append(fieldNames, pgx.Identifier{field.FieldName}.Sanitize())
+		selectFields = append(selectFields, selectField)
+	}
+	if len(fieldNames) < 1 {
+		return "", nil
+	}
+	return fmt.Sprintf("INSERT INTO %v (%v) SELECT %v FROM %v", QuoteTableName(ComputeTempTableName(model.TableName)), strings.Join(fieldNames, ", "), strings.Join(selectFields, ", "), QuoteTableName(model.TableName)), nil
+}
 
+// ForeignKeyType describes the foreign-key constraint of the other (referencing) table, that references the table
+type ForeignKeyType struct {
+	TableName      string // referencing table-name, as quoted (if required) by the db, e.g. public.orders
+	ConstraintName string
+	ConstraintDef  string // e.g. FOREIGN KEY (service_id) REFERENCES services(id) ON DELETE CASCADE
+}
 
-	return "", nil
+// GetReferencingKeys introspects the foreign-key constraints of the other tables, that reference the table
+func GetReferencingKeys(tableName string, appDb *pgxpool.Pool) ([]ForeignKeyType, error) {
+	keyQuery := "SELECT conrelid::regclass::text, conname, pg_get_constraintdef(oid) FROM pg_constraint WHERE contype = 'f' AND confrelid = to_regclass($1) AND conrelid <> confrelid ORDER BY conname"
+	rows, err := appDb.Query(context.Background(), keyQuery, QuoteTableName(tableName))
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Db query Error [referencing-keys]: %v", err.Error()))
+	}
+	defer rows.Close()
+	var foreignKeys []ForeignKeyType
+	for rows.Next() {
+		var foreignKey ForeignKeyType
+		if err = rows.Scan(&foreignKey.TableName, &foreignKey.ConstraintName, &foreignKey.ConstraintDef); err != nil {
+			return nil, errors.New(fmt.Sprintf("Error reading referencing-keys: %v", err.Error()))
+		}
+		foreignKeys = append(foreignKeys, foreignKey)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.New(fmt.Sprintf("Error reading referencing-keys: %v", err.Error()))
+	}
+	return foreignKeys, nil
 }
 
-func SyncTable(model types.ModelType, appDb *pgxpool.Pool) error {
+// ComputeRenameConstraintsQuery compose the rename script of the temporary-table constraints, after the table
+// swap, to the table constraint-names, e.g. services_mcorm_tmp_pkey => services_pkey
+func ComputeRenameConstraintsQuery(tableName string) string {
+	tableNames := strings.Split(tableName, ".")
+	baseName := tableNames[len(tableNames)-1]
+	tempName := ComputeTempTableName(baseName)
+	quotedName := strings.ReplaceAll(QuoteTableName(tableName), "'", "''")
+	return fmt.Sprintf(`DO $$
+DECLARE con record;
+BEGIN
+	FOR con IN SELECT conname FROM pg_constraint WHERE conrelid = '%v'::regclass AND left(conname, %v) = '%v' LOOP
+		EXECUTE format('ALTER TABLE %v RENAME CONSTRAINT %%I TO %%I', con.conname, '%v' || substr(con.conname, %v));
+	END LOOP;
+END $$`, quotedName, len(tempName), strings.ReplaceAll(tempName, "'", "''"), quotedName, strings.ReplaceAll(baseName, "'", "''"), len(tempName)+1)
+}
 
+// computeRetainedColumns computes the table columns not in the model table-fields (or former-names), that would
+// be dropped by the table sync
+func computeRetainedColumns(model types.ModelType, tableColumns map[string]TableColumnType) ([]string, error) {
+	modelFields, err := ComputeModelFields(model)
+	if err != nil {
+		return nil, err
+	}
+	fieldNames := map[string]bool{}
+	for _, field := range modelFields {
+		fieldNames[field.FieldName] = true
+		if field.FieldDesc.FormerName != "" {
+			fieldNames[ToSnakeCase(field.FieldDesc.FormerName)] = true
+		}
+	}
+	var columnNames []string
+	for columnName := range tableColumns {
+		if !fieldNames[columnName] {
+			columnNames = append(columnNames, columnName)
+		}
+	}
+	sort.Strings(columnNames)
+	return columnNames, nil
+}
 
+// ComputeSyncTableQuery compose the sync-table scripts, from the model, the live table-columns and the
+// referencing foreign-keys of the other tables: create the table, if not exists | re-create the table (data loss),
+// for AlterSyncTable: false with the RecreateTable opt-in, of the table not referenced by the other tables |
+// otherwise, create the temporary-table, copy and convert the existing records, swap the tables, rename the
+// constraints and create the indexes. The referencing foreign-keys are dropped before the table drop and
+// re-created (and validated) after the swap. The sync requires DropColumns, if the table has columns not in the
+// model. Returns no scripts, if the table is up-to-date with the model
+func ComputeSyncTableQuery(model types.ModelType, tableColumns map[string]TableColumnType, referencingKeys []ForeignKeyType) ([]string, error) {
+	createQuery, indexQueries, err := ComputeCreateTableQuery(model)
+	if err != nil {
+		return nil, err
+	}
+	if len(tableColumns) < 1 {
+		return append([]string{createQuery}, indexQueries...), nil
+	}
+	alterQueries, err := ComputeAlterTableQuery(model, tableColumns)
+	if err != nil {
+		return nil, err
+	}
+	if len(alterQueries) < 1 {
+		return nil, nil
+	}
+	tableName := QuoteTableName(model.TableName)
+	if !model.AlterSyncTable && model.RecreateTable {
+		// the referencing records would fail the re-created foreign-keys, of the empty table
+		if len(referencingKeys) > 0 {
+			var keyNames []string
+			for _, foreignKey := range referencingKeys {
+				keyNames = append(keyNames, fmt.Sprintf("%v.%v", foreignKey.TableName, foreignKey.ConstraintName))
+			}
+			return nil, errors.New(fmt.Sprintf("table [%v] is referenced by the foreign-keys %v, the re-create (no data sync) is not permitted, use AlterSyncTable: true", model.TableName, keyNames))
+		}
+		return append([]string{fmt.Sprintf("DROP TABLE %v", tableName), createQuery}, indexQueries...), nil
+	}
+	// drop and re-create the referencing foreign-keys, around the table drop
+	var dropKeyQueries, addKeyQueries []string
+	for _, foreignKey := range referencingKeys {
+		constraintName := pgx.Identifier{foreignKey.ConstraintName}.Sanitize()
+		dropKeyQueries = append(dropKeyQueries, fmt.Sprintf("ALTER TABLE %v DROP CONSTRAINT %v", foreignKey.TableName, constraintName))
+		addKeyQueries = append(addKeyQueries, fmt.Sprintf("ALTER TABLE %v ADD CONSTRAINT %v %v", foreignKey.TableName, constraintName, foreignKey.ConstraintDef))
+	}
+	if !model.DropColumns {
+		retainedColumns, err := computeRetainedColumns(model, tableColumns)
+		if err != nil {
+			return nil, err
+		}
+		if len(retainedColumns) > 0 {
+			return nil, errors.New(fmt.Sprintf("table [%v] columns %v are not in the model, DropColumns is required to sync (drop) them", model.TableName, retainedColumns))
+		}
+	}
+	tempQuery, err := CreateTempTableQuery(model)
+	if err != nil {
+		return nil, err
+	}
+	syncQueries := []string{tempQuery}
+	copyQuery, err := ComputeCopyTableQuery(model, tableColumns)
+	if err != nil {
+		return nil, err
+	}
+	if copyQuery != "" {
+		syncQueries = append(syncQueries, copyQuery)
+	}
+	// swap the tables: the renamed table remains in the table-schema
+	tableNames := strings.Split(model.TableName, ".")
+	syncQueries = append(append(syncQueries, dropKeyQueries...),
+		fmt.Sprintf("DROP TABLE %v", tableName),
+		fmt.Sprintf("ALTER TABLE %v RENAME TO %v", QuoteTableName(ComputeTempTableName(model.TableName)), pgx.Identifier{tableNames[len(tableNames)-1]}.Sanitize()),
+		ComputeRenameConstraintsQuery(model.TableName),
+	)
+	return append(append(syncQueries, indexQueries...), addKeyQueries...), nil
+}
+
+// SyncTableQuery compose the sync-table script, from the diff of the model and the live table, without
+// executing it (dry-run). Returns an empty script, if the table is up-to-date with the model
+func SyncTableQuery(model types.ModelType, appDb *pgxpool.Pool) (string, error) {
+	tableColumns, err := GetTableColumns(model.TableName, appDb)
+	if err != nil {
+		return "", err
+	}
+	referencingKeys, err := GetReferencingKeys(model.TableName, appDb)
+	if err != nil {
+		return "", err
+	}
+	syncQueries, err := ComputeSyncTableQuery(model, tableColumns, referencingKeys)
+	if err != nil || len(syncQueries) < 1 {
+		return "", err
+	}
+	return strings.Join(syncQueries, ";\n") + ";", nil
+}
+
+// SyncTable creates, re-creates (AlterSyncTable: false, with RecreateTable) or alters and syncs the existing records of the model
+// table, in a transaction. All changes are rolled-back on error
+func SyncTable(model types.ModelType, appDb *pgxpool.Pool) error {
+	tableColumns, err := GetTableColumns(model.TableName, appDb)
+	if err != nil {
+		return err
+	}
+	referencingKeys, err := GetReferencingKeys(model.TableName, appDb)
+	if err != nil {
+		return err
+	}
+	syncQueries, err := ComputeSyncTableQuery(model, tableColumns, referencingKeys)
+	if err != nil || len(syncQueries) < 1 {
+		return err
+	}
+	tx, err := appDb.Begin(context.Background())
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error starting the sync-table transaction: %v", err.Error()))
+	}
+	defer tx.Rollback(context.Background())
+	for _, syncQuery := range syncQueries {
+		if _, err = tx.Exec(context.Background(), syncQuery); err != nil {
+			return errors.New(fmt.Sprintf("Error syncing table [%v]: %v | rolled-back", model.TableName, err.Error()))
+		}
+	}
+	if err = tx.Commit(context.Background()); err != nil {
+		return errors.New(fmt.Sprintf("Error committing the sync-table transaction: %v", err.Error()))
+	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,139 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-15 | @Updated: 2021-01-15
+// @Company: mConnect.biz | @License: MIT
+// @Description: sync-table-query test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+var syncTableModel = types.ModelType{
+	TableName: "services",
+	RecordDesc: types.RecordDescType{
+		"title": types.FieldDescType{
+			FieldType:   datatypes.String,
+			FieldLength: 120,
+			FormerName:  "name",
+		},
+		"category": types.FieldDescType{
+			FieldType: datatypes.String,
+			Indexable: true,
+		},
+		"priority": types.FieldDescType{
+			FieldType: datatypes.BigInt,
+		},
+	},
+	AlterSyncTable: true,
+	DropColumns:    true,
+}
+
+func TestComputeSyncTableQuery(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute sync-table scripts, to copy and convert the existing records:",
+		TestFunc: func() {
+			res, err := ComputeSyncTableQuery(syncTableModel, serviceTableColumns, nil)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertStrictEquals(t, res, []string{
+				"DROP TABLE IF EXISTS \"services_mcorm_tmp\";\nCREATE TABLE IF NOT EXISTS \"services_mcorm_tmp\" (\n\t\"category\" VARCHAR(255) NOT NULL,\n\t\"priority\" BIGINT NOT NULL,\n\t\"title\" VARCHAR(120) NOT NULL\n);",
+				`INSERT INTO "services_mcorm_tmp" ("category", "priority", "title") SELECT "category", CAST("priority" AS BIGINT), "name" FROM "services"`,
+				`DROP TABLE "services"`,
+				`ALTER TABLE "services_mcorm_tmp" RENAME TO "services"`,
+				ComputeRenameConstraintsQuery("services"),
+				`CREATE INDEX IF NOT EXISTS "services_category_idx" ON "services" ("category")`,
+			}, "sync-table scripts should create, copy, swap and index the table")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should drop and re-create the referencing foreign-keys, around the table swap:",
+		TestFunc: func() {
+			referencingKeys := []ForeignKeyType{
+				{TableName: "orders", ConstraintName: "orders_service_id_fkey", ConstraintDef: "FOREIGN KEY (service_id) REFERENCES services(id)"},
+			}
+			res, err := ComputeSyncTableQuery(syncTableModel, serviceTableColumns, referencingKeys)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, len(res), 8, "sync-table scripts length should be: 8")
+			mctest.AssertEquals(t, res[2], `ALTER TABLE orders DROP CONSTRAINT "orders_service_id_fkey"`, "the foreign-key should be dropped before the table drop")
+			mctest.AssertEquals(t, res[3], `DROP TABLE "services"`, "the table should be dropped")
+			mctest.AssertEquals(t, res[7], `ALTER TABLE orders ADD CONSTRAINT "orders_service_id_fkey" FOREIGN KEY (service_id) REFERENCES services(id)`, "the foreign-key should be re-created after the swap")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should rename the temporary-table constraints, and require DropColumns for the columns not in the model:",
+		TestFunc: func() {
+			mctest.AssertEquals(t, ComputeRenameConstraintsQuery("public.services"), `DO $$
+DECLARE con record;
+BEGIN
+	FOR con IN SELECT conname FROM pg_constraint WHERE conrelid = '"public"."services"'::regclass AND left(conname, 18) = 'services_mcorm_tmp' LOOP
+		EXECUTE format('ALTER TABLE "public"."services" RENAME CONSTRAINT %I TO %I', con.conname, 'services' || substr(con.conname, 19));
+	END LOOP;
+END $$`, "rename-constraints script should match")
+			model := syncTableModel
+			model.DropColumns = false
+			_, err := ComputeSyncTableQuery(model, serviceTableColumns, nil)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute re-create table scripts, for AlterSyncTable: false with the RecreateTable opt-in:",
+		TestFunc: func() {
+			model := syncTableModel
+			model.AlterSyncTable = false
+			res, err := ComputeSyncTableQuery(model, serviceTableColumns, nil)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, len(res), 6, "sync-table scripts should copy the records, without the RecreateTable opt-in")
+			model.RecreateTable = true
+			res, err = ComputeSyncTableQuery(model, serviceTableColumns, nil)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, len(res), 3, "sync-table scripts should drop, create and index the table")
+			mctest.AssertEquals(t, res[0], `DROP TABLE "services"`, "the table should be dropped")
+			_, err = ComputeSyncTableQuery(model, serviceTableColumns, []ForeignKeyType{
+				{TableName: "orders", ConstraintName: "orders_service_id_fkey", ConstraintDef: "FOREIGN KEY (service_id) REFERENCES services(id)"},
+			})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil, for the referenced table")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should copy the default or fill values into the new NOT NULL fields:",
+		TestFunc: func() {
+			recordDesc := types.RecordDescType{
+				"code": types.FieldDescType{
+					FieldType:   datatypes.String,
+					FieldLength: 20,
+				},
+				"rating": types.FieldDescType{
+					FieldType:    datatypes.Integer,
+					DefaultValue: func() interface{} { return 5 },
+				},
+				"remark": types.FieldDescType{
+					FieldType: datatypes.Text,
+					AllowNull: true,
+				},
+			}
+			for fieldName, fieldDesc := range syncTableModel.RecordDesc {
+				recordDesc[fieldName] = fieldDesc
+			}
+			model := syncTableModel
+			model.RecordDesc = recordDesc
+			res, err := ComputeCopyTableQuery(model, serviceTableColumns)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, `INSERT INTO "services_mcorm_tmp" ("category", "code", "priority", "rating", "title") SELECT "category", '', CAST("priority" AS BIGINT), 5, "name" FROM "services"`, "copy-query should fill the new NOT NULL fields")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute create-table scripts for the new table, and no scripts for the up-to-date table:",
+		TestFunc: func() {
+			res, err := ComputeSyncTableQuery(syncTableModel, map[string]TableColumnType{}, nil)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, len(res), 2, "sync-table scripts should create and index the table")
+			res, err = ComputeSyncTableQuery(createTableModel, serviceTableColumns, nil)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, len(res), 0, "sync-table scripts should be empty")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -6,6 +6,7 @@ package helper
 
 import (
 	"errors"
+	"fmt"
 	"github.com/abbeymart/mcorm/types"
 )
 
@@ -32,28 +33,40 @@ func ComputeSaveFields(actionParams types.ActionParamsType, projectParams types.
 	return tableFields, nil
 }
 
-func ComputeGetFields(projectParams types.ProjectParamType) ([]string, error) {
+// ComputeGetFields function computes the projected table-fields, in the order of the tableFields, from the
+// inclusion (true) or exclusion (false) projectParams. Inclusion takes precedence, if specified, and the id field
+// is always included. Returns the tableFields for empty projectParams
+func ComputeGetFields(tableFields []string, projectParams types.ProjectParamType) ([]string, error) {
+	if len(tableFields) < 1 {
+		return nil, errors.New("table-fields are required to compute the select/projection-fields")
+	}
 	if len(projectParams) < 1 {
-		return nil, errors.New("select/projection-params is required")
+		return tableFields, nil
 	}
-	// obtain tableFields from api consumer (ProjectParams) | TODO: order-by-model-field-type-specs
-	var tableFields []string
-	if len(projectParams) > 0 {
-		for fieldName, ok := range projectParams {
-			if ok {
-				tableFields = append(tableFields, fieldName)
-			}
+	includeFields := false
+	for fieldName, ok := range projectParams {
+		if !ArrayStringContains(tableFields, fieldName) {
+			return nil, errors.New(fmt.Sprintf("Unknown projection field-name: %v", fieldName))
 		}
-		// include default fields (id) for get/select-query only
-		// s = append([]int{0}, s...) | s = append([]string{"id"}, s...)
-		if !ArrayStringContains(tableFields, "id") {
-			tableFields = append([]string{"id"}, tableFields...)
+		if ok {
+			includeFields = true
 		}
 	}
-	if len(tableFields) < 1 {
+	var getFields []string
+	for _, fieldName := range tableFields {
+		ok, specified := projectParams[fieldName]
+		switch {
+		case fieldName == "id":
+			getFields = append(getFields, fieldName)
+		case includeFields && specified && ok:
+			getFields = append(getFields, fieldName)
+		case !includeFields && !specified:
+			getFields = append(getFields, fieldName)
+		}
+	}
+	if len(getFields) < 1 {
 		return nil, errors.New("unable to compute query-fields")
 	}
 
-	return tableFields, nil
+	return getFields, nil
 }
-
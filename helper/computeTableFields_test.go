@@ -0,0 +1,66 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-10 | @Updated: 2021-01-10
+// @Company: mConnect.biz | @License: MIT
+// @Description: projection/table-fields test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+type projectRecord struct {
+	Id    string `json:"id" mcorm:"id"`
+	Name  string `json:"name" mcorm:"name"`
+	Email string `json:"email" mcorm:"email"`
+	Age   int    `json:"age" mcorm:"age"`
+}
+
+func TestComputeGetFields(t *testing.T) {
+	tableFields, _, _ := StructToFieldValues(projectRecord{}, "mcorm")
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the table-fields in the struct-fields order:",
+		TestFunc: func() {
+			mctest.AssertStrictEquals(t, tableFields, []string{"id", "name", "email", "age"}, "table-fields should match the struct mcorm-tags")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the included fields, with the id field:",
+		TestFunc: func() {
+			res, err := ComputeGetFields(tableFields, types.ProjectParamType{"age": true, "name": true})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertStrictEquals(t, res, []string{"id", "name", "age"}, "get-fields should be: id, name, age")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the fields, without the excluded fields, except the id field:",
+		TestFunc: func() {
+			res, err := ComputeGetFields(tableFields, types.ProjectParamType{"email": false, "id": false})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertStrictEquals(t, res, []string{"id", "name", "age"}, "get-fields should be: id, name, age")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return an error for unknown projection field-name:",
+		TestFunc: func() {
+			_, err := ComputeGetFields(tableFields, types.ProjectParamType{"salary": true})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the scan-pointers for the projected fields of a new record:",
+		TestFunc: func() {
+			rec, fieldPointers, err := StructScanFields(projectRecord{Name: "abc"}, "mcorm", []string{"id", "age"})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, len(fieldPointers), 2, "scan-pointers count should be: 2")
+			*(fieldPointers[1].(*int)) = 21
+			newRec, ok := rec.(*projectRecord)
+			mctest.AssertEquals(t, ok, true, "new record should be of type *projectRecord")
+			mctest.AssertEquals(t, newRec.Age, 21, "age should be set through the scan-pointer")
+			mctest.AssertEquals(t, newRec.Name, "", "excluded name should be zero-valued")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -1,22 +1,42 @@
-// @Author: abbeymart | Abi Akindele | @Created: 2020-12-08 | @Updated: 2020-12-08
+// @Author: abbeymart | Abi Akindele | @Created: 2020-12-08 | @Updated: 2021-01-15
 // @Company: mConnect.biz | @License: MIT
 // @Description: compute temporary-table script
 
 package helper
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/abbeymart/mcorm/types"
-	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/jackc/pgx/v4"
 )
 
-func CreateTempTableQuery(model types.ModelType, appDb *pgxpool.Pool) (string, error) {
-
-
-	return "", nil
+// ComputeTempTableName returns the temporary-table name, for the table sync, e.g. users_mcorm_tmp
+func ComputeTempTableName(tableName string) string {
+	return tableName + "_mcorm_tmp"
 }
 
-func CreateTempTable(model types.ModelType, appDb *pgxpool.Pool) error {
-
+// CreateTempTableQuery compose the (re-)create script of the temporary-table, in the new shape of the model
+// table, without the indexes. The indexes are created after the table swap (see SyncTable)
+func CreateTempTableQuery(model types.ModelType) (string, error) {
+	tempTableName := ComputeTempTableName(model.TableName)
+	model.TableName = tempTableName
+	createQuery, _, err := ComputeCreateTableQuery(model)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("DROP TABLE IF EXISTS %v;\n%v;", QuoteTableName(tempTableName), createQuery), nil
+}
 
+// CreateTempTable creates the temporary-table of the model, in the sync transaction
+func CreateTempTable(model types.ModelType, tx pgx.Tx) error {
+	tempQuery, err := CreateTempTableQuery(model)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(context.Background(), tempQuery); err != nil {
+		return errors.New(fmt.Sprintf("Error creating temp-table [%v]: %v", ComputeTempTableName(model.TableName), err.Error()))
+	}
 	return nil
 }
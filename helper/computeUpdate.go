@@ -9,199 +9,92 @@ import (
 	"errors"
 	"fmt"
 	"github.com/abbeymart/mcorm/types"
-	"github.com/asaskevich/govalidator"
+	"hash/fnv"
 	"time"
 )
 
-func ComputeUpdateQuery(tableName string, actionParams types.ActionParamsType, tableFields []string) ([]string, error) {
-	if tableName == "" || len(actionParams) < 1 {
-		return nil, errors.New("table-name and action-params are required for the update operation")
-	}
-	// compute tableFields from the first record, if len(tableFields) == 0
-	if len(tableFields) == 0 {
-		actRec := actionParams[0]
-		for fName := range actRec {
-			if fName == "id" {
-				continue
-			}
-			tableFields = append(tableFields, fName)
-		}
-	}
-	// compute update script from queryParams
-	var updateQuery []string
-	validUpdateItemCount := 0
-	invalidUpdateItemCount := 0
-
-	for recNum, rec := range actionParams {
-		itemScript := fmt.Sprintf("UPDATE %v SET", tableName)
-		fieldCount := 0
-		fieldLen := len(tableFields)
-		for _, fieldName := range tableFields {
-			fieldValue, ok := rec[fieldName]
-			// check for the required fields in each record
-			if !ok {
-				return nil, errors.New(fmt.Sprintf("Record #%v [%#v]: required field_name[%v] is missing", recNum, rec, fieldName))
-			}
-			fieldCount += 1
-			// update/set recFieldValues by fieldValue-type
-			var currentFieldValue interface{}
-			switch fieldValue.(type) {
-			case time.Time:
-				if fVal, ok := fieldValue.(time.Time); !ok {
-					return nil, errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-				} else {
-					currentFieldValue = "'" + fVal.Format("2006-01-02 15:04:05.000000") + "'"
-				}
-			case string:
-				if fVal, ok := fieldValue.(string); !ok {
-					return nil, errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-				} else {
-					if govalidator.IsJSON(fVal) {
-						if fValue, err := govalidator.ToJSON(fieldValue); err != nil {
-							return nil, errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-						} else {
-							currentFieldValue = "'" + fValue + "'"
-						}
-					} else {
-						currentFieldValue = "'" + fVal + "'"
-					}
-				}
-			case int, uint, float64, bool:
-				currentFieldValue = fieldValue
-			default:
-				// json-stringify fieldValue
-				if fVal, err := json.Marshal(fieldValue); err != nil {
-					return nil, errors.New(fmt.Sprintf("Unknown or Unsupported field-value type: %v", err.Error()))
-				} else {
-					currentFieldValue = "'" + string(fVal) + "'"
-				}
-			}
-
-			// add itemValue
-			itemScript += fmt.Sprintf(" %v=%v", fieldName, currentFieldValue)
-			if fieldLen > 1 && fieldCount < fieldLen {
-				itemScript += ", "
-			}
-		}
-
-		// add where condition by id
-		itemScript += fmt.Sprintf(" WHERE id='%v'", rec["id"])
-		//validate/update script content based on valid field specifications
-		if fieldCount > 0 && fieldCount == fieldLen {
-			validUpdateItemCount += 1
-			updateQuery = append(updateQuery, itemScript)
+// computeUpdateValue computes the placeholder-value of the update field-value: the scalar, time and slice
+// values are passed as-is, the other values (maps, structs...) are json-stringified
+func computeUpdateValue(fieldName string, fieldValue interface{}) (interface{}, error) {
+	switch fieldValue.(type) {
+	case nil, time.Time, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32,
+		float64, []string, []int, []int64, []float64, []bool:
+		return fieldValue, nil
+	default:
+		// json-stringify fieldValue
+		if fVal, err := json.Marshal(fieldValue); err != nil {
+			return nil, errors.New(fmt.Sprintf("field_name: %v | Unknown or Unsupported field-value type: %v", fieldName, err.Error()))
 		} else {
-			invalidUpdateItemCount += 1
+			return string(fVal), nil
 		}
 	}
-	// check is there was invalid update items
-	if invalidUpdateItemCount > 0 {
-		return nil, errors.New(fmt.Sprintf("Invalid action-params [%v]", invalidUpdateItemCount))
-	}
-	return updateQuery, nil
 }
 
-func ComputeUpdateQueryById(tableName string, actionParams types.ActionParamsType, recordIds []string, tableFields []string) (string, error) {
-	if tableName == "" || len(actionParams) < 1 || len(recordIds) < 1 {
-		return "", errors.New("table-name, table-fields, action-params and record/doc-Ids are required for the update-by-id operation")
+// ComputeStatementName computes the prepared-statement name of the query
+func ComputeStatementName(query string) string {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(query))
+	return fmt.Sprintf("mcorm_%x", hash.Sum64())
+}
+
+// ComputeUpdateQuery computes the reusable update-by-id statement, with $n placeholders (the id as the last
+// placeholder), and the per-record placeholder-values (RowValues), by the table-fields order. The update-operator
+// field-values (e.g. {"stock": {"$inc": -1}}) compile to the atomic SET expressions, of the same operator for
+// all the records
+func ComputeUpdateQuery(tableName string, actionParams types.ActionParamsType, tableFields []string) (types.UpdateQueryResponseType, error) {
+	if tableName == "" || len(actionParams) < 1 {
+		return types.UpdateQueryResponseType{}, errors.New("table-name and action-params are required for the update operation")
 	}
 	// compute tableFields from the first record, if len(tableFields) == 0
 	if len(tableFields) == 0 {
 		actRec := actionParams[0]
 		for fName := range actRec {
-			if fName == "id" {
-				continue
-			}
 			tableFields = append(tableFields, fName)
 		}
 	}
-	// compute update script from query-ids
-	var updateQuery string
-	itemScript := fmt.Sprintf("UPDATE %v SET", tableName)
-	// from / where condition (where-in-values)
-	whereIds := ""
-	idLen := len(recordIds)
-	for idCount, id := range recordIds {
-		whereIds += "'" + id + "'"
-		if idLen > 1 && idCount < idLen-1 {
-			whereIds += ", "
+	// update-fields, excluding the id (where condition)
+	var updateFields []string
+	for _, fieldName := range tableFields {
+		if fieldName != "id" {
+			updateFields = append(updateFields, fieldName)
 		}
 	}
-	whereQuery := fmt.Sprintf(" WHERE id IN(%v)", whereIds)
-
-	invalidUpdateItemCount := 0
-	validUpdateItemCount := 0
+	// compute update script, with value-placeholders, from the first record
+	setScript, setValues, err := ComputeSetScript(actionParams[0], updateFields, 0)
+	if err != nil {
+		return types.UpdateQueryResponseType{}, errors.New(fmt.Sprintf("Record #0: %v", err.Error()))
+	}
+	whereQuery := fmt.Sprintf("WHERE id=$%v", len(setValues)+1)
+	updateQuery := fmt.Sprintf("UPDATE %v SET %v %v", tableName, setScript, whereQuery)
 
-	// only one actionParams record is required for update by docIds
-	rec := actionParams[0]
-	fieldCount := 0
-	fieldLen := len(tableFields)
-	for _, fieldName := range tableFields {
-		fieldValue, ok := rec[fieldName]
-		// check for the required fields in each record
-		if !ok {
-			return "", errors.New(fmt.Sprintf("Record [%#v]: required field_name[%v] is missing", rec, fieldName))
+	// compute update values from actionParams
+	var rowValues [][]interface{}
+	for recNum, rec := range actionParams {
+		recSetScript, recFieldValues, err := ComputeSetScript(rec, updateFields, 0)
+		if err != nil {
+			return types.UpdateQueryResponseType{}, errors.New(fmt.Sprintf("Record #%v: %v", recNum, err.Error()))
 		}
-		fieldCount += 1
-		// update/set recFieldValues by fieldValue-type
-		var currentFieldValue interface{}
-		switch fieldValue.(type) {
-		case time.Time:
-			if fVal, ok := fieldValue.(time.Time); !ok {
-				return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-			} else {
-				currentFieldValue = "'" + fVal.Format("2006-01-02 15:04:05.000000") + "'"
-			}
-		case string:
-			if fVal, ok := fieldValue.(string); !ok {
-				return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-			} else {
-				if govalidator.IsJSON(fVal) {
-					if fValue, err := govalidator.ToJSON(fieldValue); err != nil {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						currentFieldValue = "'" + fValue + "'"
-					}
-				} else {
-					currentFieldValue = "'" + fVal + "'"
-				}
-			}
-		case int, uint, float64, bool:
-			currentFieldValue = fieldValue
-		default:
-			// json-stringify fieldValue
-			if fVal, err := json.Marshal(fieldValue); err != nil {
-				return "", errors.New(fmt.Sprintf("Unknown or Unsupported field-value type: %v", err.Error()))
-			} else {
-				currentFieldValue = "'" + string(fVal) + "'"
-			}
+		if recSetScript != setScript {
+			return types.UpdateQueryResponseType{}, errors.New(fmt.Sprintf("Record #%v [%#v]: the update-operators must match the first record", recNum, rec))
 		}
-		// add itemValue
-		itemScript += fmt.Sprintf(" %v=%v", fieldName, currentFieldValue)
-		if fieldLen > 1 && fieldCount < fieldLen {
-			itemScript += ", "
+		// where condition by id
+		if recId, ok := rec["id"]; !ok || recId == nil || recId == "" {
+			return types.UpdateQueryResponseType{}, errors.New(fmt.Sprintf("Record #%v [%#v]: required id is missing", recNum, rec))
+		} else {
+			recFieldValues = append(recFieldValues, recId)
 		}
-	}
-	//validate/update script content based on valid field specifications
-	if fieldCount > 0 && fieldCount == fieldLen {
-		validUpdateItemCount += 1
-		updateQuery = itemScript + whereQuery
-	} else {
-		invalidUpdateItemCount += 1
-	}
-
-	// check is there was invalid update items
-	if invalidUpdateItemCount > 0 {
-		return "", errors.New(fmt.Sprintf("Invalid action-params [%v]", invalidUpdateItemCount))
-	}
-	return updateQuery, nil
+		rowValues = append(rowValues, recFieldValues)
+	}
+	return types.UpdateQueryResponseType{
+		UpdateQuery: updateQuery,
+		WhereQuery:  whereQuery,
+		FieldNames:  updateFields,
+		RowValues:   rowValues,
+	}, nil
 }
 
-func ComputeUpdateQueryByParam(tableName string, actionParams types.ActionParamsType, where types.QueryParamType, tableFields []string) (string, error) {
-	if tableName == "" || len(actionParams) < 1 || len(where) < 1 {
-		return "", errors.New("table-name, action-params and where-params are required for the update-by-params operation")
-	}
-	// compute tableFields from the first record, if len(tableFields) == 0
+// computeUpdateFields computes the update-fields, from the first record, if len(tableFields) == 0
+func computeUpdateFields(actionParams types.ActionParamsType, tableFields []string) []string {
 	if len(tableFields) == 0 {
 		actRec := actionParams[0]
 		for fName := range actRec {
@@ -211,81 +104,50 @@ func ComputeUpdateQueryByParam(tableName string, actionParams types.ActionParams
 			tableFields = append(tableFields, fName)
 		}
 	}
+	return tableFields
+}
 
-	// compute update script from queryParams
-	var updateQuery string
-	invalidUpdateItemCount := 0
-	validUpdateItemCount := 0
-
-	// only one actionParams record is required for update by where-params
-	rec := actionParams[0]
-	itemScript := fmt.Sprintf("UPDATE %v SET", tableName)
-	fieldCount := 0
-	fieldLen := len(tableFields)
-	for _, fieldName := range tableFields {
-		fieldValue, ok := rec[fieldName]
-		// check for the required fields in each record
-		if !ok {
-			return "", errors.New(fmt.Sprintf("Record [%#v]: required field_name[%v] is missing", rec, fieldName))
-		}
-		fieldCount += 1
-		// update/set recFieldValues by fieldValue-type
-		var currentFieldValue interface{}
-		switch fieldValue.(type) {
-		case time.Time:
-			if fVal, ok := fieldValue.(time.Time); !ok {
-				return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-			} else {
-				currentFieldValue = "'" + fVal.Format("2006-01-02 15:04:05.000000") + "'"
-			}
-		case string:
-			if fVal, ok := fieldValue.(string); !ok {
-				return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-			} else {
-				if govalidator.IsJSON(fVal) {
-					if fValue, err := govalidator.ToJSON(fieldValue); err != nil {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						currentFieldValue = "'" + fValue + "'"
-					}
-				} else {
-					currentFieldValue = "'" + fVal + "'"
-				}
-			}
-		case int, uint, float64, bool:
-			currentFieldValue = fieldValue
-		default:
-			// json-stringify fieldValue
-			if fVal, err := json.Marshal(fieldValue); err != nil {
-				return "", errors.New(fmt.Sprintf("Unknown or Unsupported field-value type: %v", err.Error()))
-			} else {
-				currentFieldValue = "'" + string(fVal) + "'"
-			}
-		}
-		// add itemValue
-		itemScript += fmt.Sprintf(" %v=%v", fieldName, currentFieldValue)
-
-		if fieldLen > 1 && fieldCount < fieldLen {
-			itemScript += ", "
-		}
-	}
-	//validate/update script content based on valid field specifications
-	if fieldCount > 0 && fieldCount == fieldLen {
-		validUpdateItemCount += 1
-		updateQuery = itemScript
-	} else {
-		invalidUpdateItemCount += 1
-	}
-
-	// check is there was invalid update items
-	if invalidUpdateItemCount > 0 {
-		return "", errors.New(fmt.Sprintf("Invalid action-params [%v]", invalidUpdateItemCount))
+// ComputeUpdateQueryById computes the update-by-ids script, with the $n placeholders of the set-values, followed
+// by the record-ids placeholder, from the (only one) actionParams record
+func ComputeUpdateQueryById(tableName string, actionParams types.ActionParamsType, recordIds []string, tableFields []string) (types.UpdateQueryResponseType, error) {
+	if tableName == "" || len(actionParams) < 1 || len(recordIds) < 1 {
+		return types.UpdateQueryResponseType{}, errors.New("table-name, table-fields, action-params and record/doc-Ids are required for the update-by-id operation")
 	}
+	// only one actionParams record is required for update by docIds
+	setScript, setValues, err := ComputeSetScript(actionParams[0], computeUpdateFields(actionParams, tableFields), 0)
+	if err != nil {
+		return types.UpdateQueryResponseType{}, err
+	}
+	// from / where condition (id = ANY($n))
+	whereRes, err := ComputeWhereQueryByIds(recordIds, len(setValues))
+	if err != nil {
+		return types.UpdateQueryResponseType{}, errors.New(fmt.Sprintf("error computing where-query condition(s): %v", err.Error()))
+	}
+	return types.UpdateQueryResponseType{
+		UpdateQuery: fmt.Sprintf("UPDATE %v SET %v %v", tableName, setScript, whereRes.WhereQuery),
+		WhereQuery:  whereRes.WhereQuery,
+		FieldValues: append(setValues, whereRes.FieldValues...),
+	}, nil
+}
 
-	if whereScript, err := ComputeWhereQuery(where); err == nil {
-		updateQuery += " " + whereScript
-		return updateQuery, nil
-	} else {
-		return "", errors.New(fmt.Sprintf("error computing where-query condition(s): %v", err.Error()))
+// ComputeUpdateQueryByParam computes the update-by-params script, with the $n placeholders of the set-values,
+// followed by the where-params placeholders, from the (only one) actionParams record
+func ComputeUpdateQueryByParam(tableName string, actionParams types.ActionParamsType, where types.QueryParamType, tableFields []string) (types.UpdateQueryResponseType, error) {
+	if tableName == "" || len(actionParams) < 1 || len(where) < 1 {
+		return types.UpdateQueryResponseType{}, errors.New("table-name, action-params and where-params are required for the update-by-params operation")
 	}
+	// only one actionParams record is required for update by where-params
+	setScript, setValues, err := ComputeSetScript(actionParams[0], computeUpdateFields(actionParams, tableFields), 0)
+	if err != nil {
+		return types.UpdateQueryResponseType{}, err
+	}
+	whereRes, err := ComputeWhereQuery(where, len(setValues))
+	if err != nil {
+		return types.UpdateQueryResponseType{}, errors.New(fmt.Sprintf("error computing where-query condition(s): %v", err.Error()))
+	}
+	return types.UpdateQueryResponseType{
+		UpdateQuery: fmt.Sprintf("UPDATE %v SET %v %v", tableName, setScript, whereRes.WhereQuery),
+		WhereQuery:  whereRes.WhereQuery,
+		FieldValues: append(setValues, whereRes.FieldValues...),
+	}, nil
 }
@@ -0,0 +1,168 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-20 | @Updated: 2021-01-20
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute the update-operator (atomic field-update) SET expressions
+
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/updateOperators"
+	"strings"
+)
+
+// IsUpdateOperator checks if the operator is a known update-operator, e.g. $inc
+func IsUpdateOperator(operator string) bool {
+	switch operator {
+	case updateOperators.Inc, updateOperators.Dec, updateOperators.Mul, updateOperators.Append, updateOperators.Remove,
+		updateOperators.JsonSet, updateOperators.JsonMerge, updateOperators.Now:
+		return true
+	default:
+		return false
+	}
+}
+
+// ComputeUpdateOperator returns the update-operator and the operand of the field-value, e.g. {"$inc": -1} =>
+// $inc, -1. The plain (set-to) field-values, including the jsonb-values with the unknown $keys (e.g. {"$ref": r}),
+// are not operators
+func ComputeUpdateOperator(fieldValue interface{}) (string, interface{}, bool) {
+	var opValue map[string]interface{}
+	switch val := fieldValue.(type) {
+	case map[string]interface{}:
+		opValue = val
+	case types.ActionParamType:
+		opValue = val
+	default:
+		return "", nil, false
+	}
+	if len(opValue) != 1 {
+		return "", nil, false
+	}
+	for operator, operand := range opValue {
+		if IsUpdateOperator(operator) {
+			return operator, operand, true
+		}
+	}
+	return "", nil, false
+}
+
+// isNumberOperand validates the numeric operand of the arithmetic update-operators
+func isNumberOperand(operand interface{}) bool {
+	switch operand.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// computeJsonOperand json-stringifies the jsonb (merge) operand, the strings are passed as json-text
+func computeJsonOperand(operand interface{}) (interface{}, error) {
+	if val, ok := operand.(string); ok {
+		return val, nil
+	}
+	jsonValue, err := json.Marshal(operand)
+	if err != nil {
+		return nil, err
+	}
+	return string(jsonValue), nil
+}
+
+// ComputeFieldUpdate compose the SET expression of the field-value (the plain value or the update-operator), with
+// the $n placeholders after the placeholder offset, and returns the placeholder-values, e.g. {"$inc": -1} =>
+// stock=stock+$1, [-1]
+func ComputeFieldUpdate(fieldName string, fieldValue interface{}, offset int) (string, []interface{}, error) {
+	if !IsFieldName(fieldName) {
+		return "", nil, errors.New(fmt.Sprintf("Invalid field-name: %v", fieldName))
+	}
+	placeholder := fmt.Sprintf("$%v", offset+1)
+	operator, operand, ok := ComputeUpdateOperator(fieldValue)
+	if !ok {
+		value, err := computeUpdateValue(fieldName, fieldValue)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%v=%v", fieldName, placeholder), []interface{}{value}, nil
+	}
+	switch operator {
+	case updateOperators.Inc, updateOperators.Dec, updateOperators.Mul:
+		if !isNumberOperand(operand) {
+			return "", nil, errors.New(fmt.Sprintf("field_name: %v | %v operand must be a number: %v", fieldName, operator, operand))
+		}
+		sqlOperator := map[string]string{updateOperators.Inc: "+", updateOperators.Dec: "-", updateOperators.Mul: "*"}[operator]
+		return fmt.Sprintf("%v=%v%v%v", fieldName, fieldName, sqlOperator, placeholder), []interface{}{operand}, nil
+	case updateOperators.Append, updateOperators.Remove:
+		if operand == nil {
+			return "", nil, errors.New(fmt.Sprintf("field_name: %v | %v operand is required", fieldName, operator))
+		}
+		arrayFunc := "array_append"
+		if operator == updateOperators.Remove {
+			arrayFunc = "array_remove"
+		}
+		return fmt.Sprintf("%v=%v(%v, %v)", fieldName, arrayFunc, fieldName, placeholder), []interface{}{operand}, nil
+	case updateOperators.JsonSet:
+		var jsonSet map[string]interface{}
+		switch val := operand.(type) {
+		case map[string]interface{}:
+			jsonSet = val
+		case types.ActionParamType:
+			jsonSet = val
+		}
+		if jsonSet == nil {
+			return "", nil, errors.New(fmt.Sprintf("field_name: %v | %v operand must be: {\"path\": p, \"value\": v}", fieldName, operator))
+		}
+		var path []string
+		switch pathValue := jsonSet["path"].(type) {
+		case string:
+			path = strings.Split(pathValue, ".")
+		case []string:
+			path = pathValue
+		}
+		value, hasValue := jsonSet["value"]
+		if len(path) < 1 || path[0] == "" || !hasValue {
+			return "", nil, errors.New(fmt.Sprintf("field_name: %v | %v path and value are required", fieldName, operator))
+		}
+		// the path-value is a json-value, i.e. the strings are json-stringified
+		jsonValue, err := json.Marshal(value)
+		if err != nil {
+			return "", nil, errors.New(fmt.Sprintf("field_name: %v | %v value error: %v", fieldName, operator, err.Error()))
+		}
+		return fmt.Sprintf("%v=jsonb_set(COALESCE(%v, '{}'::jsonb), %v::text[], $%v::jsonb)", fieldName, fieldName, placeholder, offset+2), []interface{}{path, string(jsonValue)}, nil
+	case updateOperators.JsonMerge:
+		jsonValue, err := computeJsonOperand(operand)
+		if err != nil {
+			return "", nil, errors.New(fmt.Sprintf("field_name: %v | %v value error: %v", fieldName, operator, err.Error()))
+		}
+		return fmt.Sprintf("%v=COALESCE(%v, '{}'::jsonb) || %v::jsonb", fieldName, fieldName, placeholder), []interface{}{jsonValue}, nil
+	case updateOperators.Now:
+		return fmt.Sprintf("%v=CURRENT_TIMESTAMP", fieldName), nil, nil
+	default:
+		return "", nil, errors.New(fmt.Sprintf("field_name: %v | unknown update-operator: %v", fieldName, operator))
+	}
+}
+
+// ComputeSetScript compose the SET script of the update-fields of the record, with the $n placeholders after the
+// placeholder offset, and returns the placeholder-values
+func ComputeSetScript(rec types.ActionParamType, updateFields []string, offset int) (string, []interface{}, error) {
+	var setFields []string
+	var setValues []interface{}
+	for _, fieldName := range updateFields {
+		fieldValue, ok := rec[fieldName]
+		// check for the required fields in each record
+		if !ok {
+			return "", nil, errors.New(fmt.Sprintf("Record [%#v]: required field_name[%v] is missing", rec, fieldName))
+		}
+		fieldUpdate, fieldValues, err := ComputeFieldUpdate(fieldName, fieldValue, offset+len(setValues))
+		if err != nil {
+			return "", nil, err
+		}
+		setFields = append(setFields, fieldUpdate)
+		setValues = append(setValues, fieldValues...)
+	}
+	if len(setFields) < 1 {
+		return "", nil, errors.New("table-fields are required for the update operation")
+	}
+	return strings.Join(setFields, ", "), setValues, nil
+}
@@ -0,0 +1,81 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-20 | @Updated: 2021-01-20
+// @Company: mConnect.biz | @License: MIT
+// @Description: update-operator (atomic field-update) test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+func TestComputeUpdateOperator(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the atomic SET expressions, of the update-operators:",
+		TestFunc: func() {
+			res, values, err := ComputeFieldUpdate("stock", map[string]interface{}{"$inc": -1}, 0)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, "stock=stock+$1", "inc-expression should match")
+			mctest.AssertStrictEquals(t, values, []interface{}{-1}, "inc-values should be: [-1]")
+			res, _, _ = ComputeFieldUpdate("cost", types.ActionParamType{"$mul": 1.5}, 2)
+			mctest.AssertEquals(t, res, "cost=cost*$3", "mul-expression should match")
+			res, _, _ = ComputeFieldUpdate("tags", map[string]interface{}{"$remove": "weekly"}, 0)
+			mctest.AssertEquals(t, res, "tags=array_remove(tags, $1)", "remove-expression should match")
+			res, values, _ = ComputeFieldUpdate("meta", map[string]interface{}{"$jsonSet": map[string]interface{}{"path": "address.city", "value": "Lagos"}}, 0)
+			mctest.AssertEquals(t, res, "meta=jsonb_set(COALESCE(meta, '{}'::jsonb), $1::text[], $2::jsonb)", "jsonSet-expression should match")
+			mctest.AssertStrictEquals(t, values, []interface{}{[]string{"address", "city"}, `"Lagos"`}, "jsonSet-values should match")
+			res, values, _ = ComputeFieldUpdate("updated_at", map[string]interface{}{"$now": true}, 0)
+			mctest.AssertEquals(t, res, "updated_at=CURRENT_TIMESTAMP", "now-expression should match")
+			mctest.AssertEquals(t, len(values), 0, "now-values length should be: 0")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return error, for the invalid update-operator operand:",
+		TestFunc: func() {
+			_, _, err := ComputeFieldUpdate("stock", map[string]interface{}{"$inc": "one"}, 0)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			_, _, err = ComputeFieldUpdate("tags", map[string]interface{}{"$append": nil}, 0)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the plain (jsonb) value, for the unknown $key:",
+		TestFunc: func() {
+			_, _, isOperator := ComputeUpdateOperator(map[string]interface{}{"$ref": "#/definitions/address"})
+			mctest.AssertEquals(t, isOperator, false, "isOperator should be: false")
+			res, values, err := ComputeFieldUpdate("meta", map[string]interface{}{"$ref": "#/definitions/address"}, 0)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, "meta=$1", "set-expression should match")
+			mctest.AssertStrictEquals(t, values, []interface{}{`{"$ref":"#/definitions/address"}`}, "set-values should match")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute update-query with the update-operators and the plain values:",
+		TestFunc: func() {
+			res, err := ComputeUpdateQuery("products", types.ActionParamsType{
+				{"id": "6900d9f9-2ceb-450f-9a9e-527eb66c962f", "name": "Soap", "stock": map[string]interface{}{"$inc": -1}},
+				{"id": "b73c8e91-61a1-4d3a-a9a3-6e3ef6bb1a1f", "name": "Sponge", "stock": map[string]interface{}{"$inc": -2}},
+			}, []string{"id", "name", "stock"})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.UpdateQuery, "UPDATE products SET name=$1, stock=stock+$2 WHERE id=$3", "update-query should match")
+			mctest.AssertStrictEquals(t, res.RowValues[1], []interface{}{"Sponge", -2, "b73c8e91-61a1-4d3a-a9a3-6e3ef6bb1a1f"}, "row-values should match")
+			_, err = ComputeUpdateQuery("products", types.ActionParamsType{
+				{"id": "6900d9f9-2ceb-450f-9a9e-527eb66c962f", "stock": map[string]interface{}{"$inc": -1}},
+				{"id": "b73c8e91-61a1-4d3a-a9a3-6e3ef6bb1a1f", "stock": 10},
+			}, []string{"id", "stock"})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil, for the mismatched update-operators")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute update-by-id query, with the where placeholders after the set-values:",
+		TestFunc: func() {
+			res, err := ComputeUpdateQueryById("products", types.ActionParamsType{{"stock": map[string]interface{}{"$dec": 3}}}, []string{"6900d9f9-2ceb-450f-9a9e-527eb66c962f"}, []string{"stock"})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.UpdateQuery, "UPDATE products SET stock=stock-$1 WHERE id = ANY($2)", "update-by-id query should match")
+			mctest.AssertEquals(t, len(res.FieldValues), 2, "field-values length should be: 2")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -0,0 +1,49 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-19 | @Updated: 2021-01-19
+// @Company: mConnect.biz | @License: MIT
+// @Description: update-query test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+func TestComputeUpdateQuery(t *testing.T) {
+	actionParams := types.ActionParamsType{
+		{"id": "6900d9f9-2ceb-450f-9a9e-527eb66c962f", "name": "O'Brien Services", "priority": 100, "tags": map[string]interface{}{"weekly": true}},
+		{"id": "b73c8e91-61a1-4d3a-a9a3-6e3ef6bb1a1f", "name": "Abi's Laundry", "priority": 50, "tags": nil},
+	}
+	tableFields := []string{"id", "name", "priority", "tags"}
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute reusable update-query, with placeholders and per-record values:",
+		TestFunc: func() {
+			res, err := ComputeUpdateQuery("services", actionParams, tableFields)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.UpdateQuery, "UPDATE services SET name=$1, priority=$2, tags=$3 WHERE id=$4", "update-query should match")
+			mctest.AssertStrictEquals(t, res.FieldNames, []string{"name", "priority", "tags"}, "update-fields should exclude the id")
+			mctest.AssertEquals(t, len(res.RowValues), 2, "row-values length should be: 2")
+			mctest.AssertStrictEquals(t, res.RowValues[0], []interface{}{"O'Brien Services", 100, `{"weekly":true}`, "6900d9f9-2ceb-450f-9a9e-527eb66c962f"}, "row-values should not be quoted")
+			mctest.AssertStrictEquals(t, res.RowValues[1], []interface{}{"Abi's Laundry", 50, nil, "b73c8e91-61a1-4d3a-a9a3-6e3ef6bb1a1f"}, "row-values should not be quoted")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return error, for the record without id or missing table-field:",
+		TestFunc: func() {
+			_, err := ComputeUpdateQuery("services", types.ActionParamsType{{"name": "Cleaning", "priority": 1, "tags": nil}}, tableFields)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			_, err = ComputeUpdateQuery("services", types.ActionParamsType{{"id": "6900d9f9-2ceb-450f-9a9e-527eb66c962f", "name": "Cleaning"}}, tableFields)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the same statement-name, for the same query:",
+		TestFunc: func() {
+			mctest.AssertEquals(t, ComputeStatementName("UPDATE services SET name=$1 WHERE id=$2"), ComputeStatementName("UPDATE services SET name=$1 WHERE id=$2"), "statement-names should match")
+			mctest.AssertNotEquals(t, ComputeStatementName("UPDATE services SET name=$1 WHERE id=$2"), ComputeStatementName("UPDATE services SET cost=$1 WHERE id=$2"), "statement-names should not match")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -0,0 +1,107 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-19 | @Updated: 2021-01-19
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute upsert (INSERT ... ON CONFLICT) script, for the create-batch operation
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/jackc/pgx/v4"
+	"strings"
+)
+
+// ComputeUniqueFields computes the (single-field) unique-fields of the model-fields
+func ComputeUniqueFields(modelFields []ModelFieldType) types.UniqueFieldsType {
+	var uniqueFields types.UniqueFieldsType
+	for _, field := range modelFields {
+		if field.FieldDesc.Unique {
+			uniqueFields = append(uniqueFields, []string{field.FieldName})
+		}
+	}
+	return uniqueFields
+}
+
+// ComputeConflictFields computes the upsert conflict-target: the specified conflict-fields, or the first of the
+// primary-fields and the unique-fields, with all the fields included in the (insert) table-fields
+func ComputeConflictFields(tableFields []string, upsertParams types.UpsertParamType, primaryFields []string, uniqueFields types.UniqueFieldsType) ([]string, error) {
+	if len(upsertParams.ConflictFields) > 0 {
+		for _, fieldName := range upsertParams.ConflictFields {
+			if !IsFieldName(fieldName) {
+				return nil, errors.New(fmt.Sprintf("Invalid conflict field-name: %v", fieldName))
+			}
+		}
+		return upsertParams.ConflictFields, nil
+	}
+	candidateFields := append([][]string{primaryFields}, uniqueFields...)
+	for _, fields := range candidateFields {
+		if len(fields) < 1 {
+			continue
+		}
+		included := true
+		for _, fieldName := range fields {
+			if !ArrayStringContains(tableFields, fieldName) {
+				included = false
+				break
+			}
+		}
+		if included {
+			return fields, nil
+		}
+	}
+	return nil, errors.New("conflict-fields are required: no primary-fields or unique-fields included in the table-fields")
+}
+
+// ComputeUpsertScript compose the ON CONFLICT script of the upsert query, returning the record-id and the
+// inserted flag (xmax = 0) of the inserted/updated records. The update-fields default to the table-fields,
+// except the conflict-fields and the id
+func ComputeUpsertScript(tableFields []string, upsertParams types.UpsertParamType, primaryFields []string, uniqueFields types.UniqueFieldsType) (string, error) {
+	conflictFields, err := ComputeConflictFields(tableFields, upsertParams, primaryFields, uniqueFields)
+	if err != nil {
+		return "", err
+	}
+	var conflictTarget []string
+	for _, fieldName := range conflictFields {
+		conflictTarget = append(conflictTarget, pgx.Identifier{fieldName}.Sanitize())
+	}
+	upsertScript := fmt.Sprintf(" ON CONFLICT (%v)", strings.Join(conflictTarget, ", "))
+	if upsertParams.DoNothing {
+		return upsertScript + " DO NOTHING RETURNING id, (xmax = 0) AS inserted", nil
+	}
+	updateFields := upsertParams.UpdateFields
+	if len(updateFields) == 0 {
+		for _, fieldName := range tableFields {
+			if fieldName != "id" && !ArrayStringContains(conflictFields, fieldName) {
+				updateFields = append(updateFields, fieldName)
+			}
+		}
+	}
+	if len(updateFields) == 0 {
+		return "", errors.New("update-fields are required for the upsert DO UPDATE, or specify DO NOTHING")
+	}
+	var updateSet []string
+	for _, fieldName := range updateFields {
+		if !IsFieldName(fieldName) || !ArrayStringContains(tableFields, fieldName) {
+			return "", errors.New(fmt.Sprintf("Invalid update field-name: %v | must be one of the table-fields", fieldName))
+		}
+		field := pgx.Identifier{fieldName}.Sanitize()
+		updateSet = append(updateSet, fmt.Sprintf("%v = EXCLUDED.%v", field, field))
+	}
+	return upsertScript + fmt.Sprintf(" DO UPDATE SET %v RETURNING id, (xmax = 0) AS inserted", strings.Join(updateSet, ", ")), nil
+}
+
+// ComputeUpsertQuery computes the upsert (INSERT ... ON CONFLICT) query and the placeholder-values of the
+// create-records (actionParams)
+func ComputeUpsertQuery(tableName string, actionParams types.ActionParamsType, tableFields []string, upsertParams types.UpsertParamType, primaryFields []string, uniqueFields types.UniqueFieldsType) (types.CreateQueryResponseType, error) {
+	createQuery, err := ComputeCreateCopyQuery(tableName, actionParams, tableFields)
+	if err != nil {
+		return createQuery, err
+	}
+	upsertScript, err := ComputeUpsertScript(createQuery.FieldNames, upsertParams, primaryFields, uniqueFields)
+	if err != nil {
+		return errMessage(err.Error())
+	}
+	createQuery.CreateQuery = strings.TrimSuffix(createQuery.CreateQuery, " RETURNING id") + upsertScript
+	return createQuery, nil
+}
@@ -0,0 +1,71 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-19 | @Updated: 2021-01-19
+// @Company: mConnect.biz | @License: MIT
+// @Description: upsert-query test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+var upsertActionParams = types.ActionParamsType{
+	{"code": "NG", "name": "Nigeria", "currency": "NGN"},
+	{"code": "GH", "name": "Ghana", "currency": "GHS"},
+}
+
+func TestComputeUpsertQuery(t *testing.T) {
+	tableFields := []string{"code", "name", "currency"}
+	uniqueFields := types.UniqueFieldsType{{"code"}}
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute upsert-query, with the unique-fields conflict-target and DO UPDATE of the other table-fields:",
+		TestFunc: func() {
+			res, err := ComputeUpsertQuery("countries", upsertActionParams, tableFields, types.UpsertParamType{Upsert: true}, []string{"id"}, uniqueFields)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.CreateQuery, "INSERT INTO countries( code,  name,  currency ) VALUES( $1,  $2,  $3 ) ON CONFLICT (\"code\") DO UPDATE SET \"name\" = EXCLUDED.\"name\", \"currency\" = EXCLUDED.\"currency\" RETURNING id, (xmax = 0) AS inserted", "upsert-query should be: ON CONFLICT (code) DO UPDATE")
+			mctest.AssertEquals(t, len(res.FieldValues), 2, "upsert-values length should be: 2")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute upsert-script, with the primary-fields conflict-target and the specified update-fields:",
+		TestFunc: func() {
+			res, err := ComputeUpsertScript([]string{"id", "code", "name", "currency"}, types.UpsertParamType{Upsert: true, UpdateFields: []string{"name"}}, []string{"id"}, uniqueFields)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, " ON CONFLICT (\"id\") DO UPDATE SET \"name\" = EXCLUDED.\"name\" RETURNING id, (xmax = 0) AS inserted", "upsert-script should be: ON CONFLICT (id) DO UPDATE SET name")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute upsert-script, with the conflict-fields and DO NOTHING:",
+		TestFunc: func() {
+			res, err := ComputeUpsertScript(tableFields, types.UpsertParamType{Upsert: true, ConflictFields: []string{"code", "currency"}, DoNothing: true}, nil, nil)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, " ON CONFLICT (\"code\", \"currency\") DO NOTHING RETURNING id, (xmax = 0) AS inserted", "upsert-script should be: ON CONFLICT (code, currency) DO NOTHING")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return error, for the conflict-target not included in the table-fields:",
+		TestFunc: func() {
+			_, err := ComputeUpsertScript([]string{"name", "currency"}, types.UpsertParamType{Upsert: true}, []string{"id"}, uniqueFields)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return error, for the update-field not included in the table-fields:",
+		TestFunc: func() {
+			_, err := ComputeUpsertScript(tableFields, types.UpsertParamType{Upsert: true, UpdateFields: []string{"population"}}, nil, uniqueFields)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the unique-fields of the model-fields:",
+		TestFunc: func() {
+			modelFields, err := ComputeModelFields(createTableModel)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			res := ComputeUniqueFields(modelFields)
+			mctest.AssertStrictEquals(t, res, types.UniqueFieldsType{{"name"}}, "unique-fields should be: [[name]]")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -0,0 +1,110 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-20 | @Updated: 2021-01-20
+// @Company: mConnect.biz | @License: MIT
+// @Description: compute optimistic-concurrency (version-checked) update-SQL scripts
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"strings"
+	"time"
+)
+
+// ComputeVersionField computes the optimistic-concurrency (table) version-field of the model: the VersionField,
+// or version, for the VersionStamp | empty, if not enabled
+func ComputeVersionField(model types.ModelType) string {
+	if model.VersionField != "" {
+		return ToSnakeCase(model.VersionField)
+	}
+	if model.VersionStamp {
+		return "version"
+	}
+	return ""
+}
+
+// computeVersionSet compose the version-field update: CURRENT_TIMESTAMP, for the time (e.g. updatedAt)
+// version-value, or the version increment
+func computeVersionSet(versionField string, versionValue interface{}) string {
+	if _, ok := versionValue.(time.Time); ok {
+		return fmt.Sprintf("%v=CURRENT_TIMESTAMP", versionField)
+	}
+	return fmt.Sprintf("%v=%v+1", versionField, versionField)
+}
+
+// computeVersionFields computes the update table-fields, excluding the version-field
+func computeVersionFields(actionParams types.ActionParamsType, tableFields []string, versionField string) []string {
+	// compute tableFields from the first record, if len(tableFields) == 0
+	if len(tableFields) == 0 {
+		for fName := range actionParams[0] {
+			tableFields = append(tableFields, fName)
+		}
+	}
+	var updateFields []string
+	for _, fieldName := range tableFields {
+		if fieldName != versionField {
+			updateFields = append(updateFields, fieldName)
+		}
+	}
+	return updateFields
+}
+
+// ComputeVersionUpdateQuery computes the reusable update-by-id statement (see ComputeUpdateQuery), checked by
+// the current version-value of each record (WHERE version=$n), and updating (incrementing) the version-field
+func ComputeVersionUpdateQuery(tableName string, actionParams types.ActionParamsType, tableFields []string, versionField string) (types.UpdateQueryResponseType, error) {
+	if !IsFieldName(versionField) || len(actionParams) < 1 {
+		return types.UpdateQueryResponseType{}, errors.New("valid version-field and action-params are required for the version-checked update operation")
+	}
+	updateQuery, err := ComputeUpdateQuery(tableName, actionParams, computeVersionFields(actionParams, tableFields, versionField))
+	if err != nil {
+		return updateQuery, err
+	}
+	placeholder := len(updateQuery.RowValues[0]) + 1
+	for recNum, rec := range actionParams {
+		versionValue, ok := rec[versionField]
+		if !ok || versionValue == nil {
+			return types.UpdateQueryResponseType{}, errors.New(fmt.Sprintf("Record #%v [%#v]: required version-field[%v] is missing", recNum, rec, versionField))
+		}
+		updateQuery.RowValues[recNum] = append(updateQuery.RowValues[recNum], versionValue)
+	}
+	setScript := strings.TrimSuffix(updateQuery.UpdateQuery, " "+updateQuery.WhereQuery)
+	whereQuery := updateQuery.WhereQuery + fmt.Sprintf(" AND %v=$%v", versionField, placeholder)
+	updateQuery.UpdateQuery = fmt.Sprintf("%v, %v %v", setScript, computeVersionSet(versionField, actionParams[0][versionField]), whereQuery)
+	updateQuery.WhereQuery = whereQuery
+	return updateQuery, nil
+}
+
+// ComputeVersionUpdateQueryById computes the update-by-ids script (see ComputeUpdateQueryById), checked by the
+// current version-value of the record (actionParams), updating (incrementing) the version-field and returning the
+// updated record-ids, to compute the stale records
+func ComputeVersionUpdateQueryById(tableName string, actionParams types.ActionParamsType, recordIds []string, tableFields []string, versionField string) (types.UpdateQueryResponseType, error) {
+	if !IsFieldName(versionField) || len(actionParams) < 1 {
+		return types.UpdateQueryResponseType{}, errors.New("valid version-field and action-params are required for the version-checked update operation")
+	}
+	versionValue, ok := actionParams[0][versionField]
+	if !ok || versionValue == nil {
+		return types.UpdateQueryResponseType{}, errors.New(fmt.Sprintf("Record [%#v]: required version-field[%v] is missing", actionParams[0], versionField))
+	}
+	updateQuery, err := ComputeUpdateQueryById(tableName, actionParams, recordIds, computeVersionFields(actionParams, tableFields, versionField))
+	if err != nil {
+		return updateQuery, err
+	}
+	setScript := strings.TrimSuffix(updateQuery.UpdateQuery, " "+updateQuery.WhereQuery)
+	whereQuery := updateQuery.WhereQuery + fmt.Sprintf(" AND %v=$%v", versionField, len(updateQuery.FieldValues)+1)
+	updateQuery.UpdateQuery = fmt.Sprintf("%v, %v %v RETURNING id", setScript, computeVersionSet(versionField, versionValue), whereQuery)
+	updateQuery.WhereQuery = whereQuery
+	updateQuery.FieldValues = append(updateQuery.FieldValues, versionValue)
+	return updateQuery, nil
+}
+
+// ComputeStaleRecordIds computes the stale (not updated) record-ids, of the version-checked update
+func ComputeStaleRecordIds(recordIds []string, updatedIds []string) []string {
+	var staleIds []string
+	for _, recordId := range recordIds {
+		if !ArrayStringContains(updatedIds, recordId) {
+			staleIds = append(staleIds, recordId)
+		}
+	}
+	return staleIds
+}
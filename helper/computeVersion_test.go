@@ -0,0 +1,82 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-20 | @Updated: 2021-01-20
+// @Company: mConnect.biz | @License: MIT
+// @Description: version-checked (optimistic-concurrency) update-query test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"github.com/abbeymart/mctest"
+	"testing"
+	"time"
+)
+
+func TestComputeVersionUpdateQuery(t *testing.T) {
+	actionParams := types.ActionParamsType{
+		{"id": "6900d9f9-2ceb-450f-9a9e-527eb66c962f", "name": "Cleaning", "version": 3},
+		{"id": "b73c8e91-61a1-4d3a-a9a3-6e3ef6bb1a1f", "name": "Laundry", "version": 1},
+	}
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute version-checked update-query, incrementing the version:",
+		TestFunc: func() {
+			res, err := ComputeVersionUpdateQuery("services", actionParams, []string{"id", "name", "version"}, "version")
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.UpdateQuery, "UPDATE services SET name=$1, version=version+1 WHERE id=$2 AND version=$3", "update-query should match")
+			mctest.AssertStrictEquals(t, res.RowValues[0], []interface{}{"Cleaning", "6900d9f9-2ceb-450f-9a9e-527eb66c962f", 3}, "row-values should end with the version-value")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute updatedAt-checked update-query, setting the current timestamp:",
+		TestFunc: func() {
+			updatedAt := time.Date(2021, 1, 20, 8, 0, 0, 0, time.UTC)
+			res, err := ComputeVersionUpdateQuery("services", types.ActionParamsType{
+				{"id": "6900d9f9-2ceb-450f-9a9e-527eb66c962f", "name": "Cleaning", "updated_at": updatedAt},
+			}, []string{"name", "updated_at"}, "updated_at")
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.UpdateQuery, "UPDATE services SET name=$1, updated_at=CURRENT_TIMESTAMP WHERE id=$2 AND updated_at=$3", "update-query should match")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return error, for the record without the version-value:",
+		TestFunc: func() {
+			_, err := ComputeVersionUpdateQuery("services", types.ActionParamsType{
+				{"id": "6900d9f9-2ceb-450f-9a9e-527eb66c962f", "name": "Cleaning"},
+			}, []string{"name"}, "version")
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute version-checked update-by-id query, returning the updated ids:",
+		TestFunc: func() {
+			res, err := ComputeVersionUpdateQueryById("services", types.ActionParamsType{{"priority": 1, "version": 2}}, []string{"6900d9f9-2ceb-450f-9a9e-527eb66c962f"}, []string{"priority", "version"}, "version")
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.UpdateQuery, "UPDATE services SET priority=$1, version=version+1 WHERE id = ANY($2) AND version=$3 RETURNING id", "update-by-id query should match")
+			mctest.AssertEquals(t, len(res.FieldValues), 3, "field-values length should be: 3")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the stale record-ids and the model version-field:",
+		TestFunc: func() {
+			res := ComputeStaleRecordIds([]string{"a", "b", "c"}, []string{"b"})
+			mctest.AssertStrictEquals(t, res, []string{"a", "c"}, "stale record-ids should be: [a c]")
+			mctest.AssertEquals(t, ComputeVersionField(types.ModelType{VersionStamp: true}), "version", "version-field should be: version")
+			mctest.AssertEquals(t, ComputeVersionField(types.ModelType{VersionField: "updatedAt"}), "updated_at", "version-field should be: updated_at")
+			mctest.AssertEquals(t, ComputeVersionField(types.ModelType{}), "", "version-field should be: empty")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should add the version-field to the table-fields, for the version-stamp model:",
+		TestFunc: func() {
+			modelFields, err := ComputeModelFields(types.ModelType{
+				TableName:    "services",
+				RecordDesc:   types.RecordDescType{"name": types.FieldDescType{FieldType: datatypes.String}},
+				VersionStamp: true,
+			})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, modelFields[len(modelFields)-1].FieldName, "version", "last table-field should be: version")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -15,44 +15,55 @@ import (
 	"time"
 )
 
-// ComputeWhereQuery function computes the multi-cases where-conditions for crud-operations
-func ComputeWhereQuery(where types.QueryParamType) (string, error) {
+// ComputeWhereQuery function computes the multi-cases where-conditions for crud-operations.
+// The field-values are returned as placeholder-values ($n), numbered from fieldLength+1, i.e. fieldLength
+// is the count of placeholders already used by the calling query (e.g. update set-values), 0 otherwise
+func ComputeWhereQuery(where types.QueryParamType, fieldLength int) (types.WhereQueryResponseType, error) {
+	return computeConditionQuery(where, fieldLength, "WHERE", nil)
+}
+
+// ComputeHavingQuery function computes the multi-cases having-conditions for aggregate-queries, with the
+// placeholder-values numbered from fieldLength+1. The field-names (e.g. aggregate-aliases or group-fields) are
+// composed into the script from their fieldExprs, e.g. {"sum_amount": "SUM(amount)"}
+func ComputeHavingQuery(having types.QueryParamType, fieldLength int, fieldExprs map[string]string) (types.WhereQueryResponseType, error) {
+	return computeConditionQuery(having, fieldLength, "HAVING", fieldExprs)
+}
+
+// computeConditionQuery computes the where/having-conditions script, prefixed by the keyword. For nil fieldExprs,
+// the field-names are composed into the script as-is, after validation
+func computeConditionQuery(where types.QueryParamType, fieldLength int, keyword string, fieldExprs map[string]string) (types.WhereQueryResponseType, error) {
 	if len(where) < 1 {
-		return "", errors.New("where condition is required")
+		return types.WhereQueryResponseType{}, errors.New("where condition is required")
 	}
-	// groups length/size
-	groupsLen := len(where)
-	// variable for empty group (i.e. contains no fieldItems)
-	emptyGroupCount := 0
-	// variable for valid group count, i.e. group with groupItems
-	groupCount := 0
+	// valid group-scripts and the group-link-operators to the next group
+	var (
+		groupScripts []string
+		groupLinkOps []string
+	)
+	// placeholder-values, in order of the placeholder-position ($n)
+	var fieldValues []interface{}
 	// sort where by groupOrder (ASC)
 	sort.SliceStable(where, func(i, j int) bool {
 		return where[i].GroupOrder < where[j].GroupOrder
 	})
 	// compute where script from where
 	// iterate through where (groups)
-	whereQuery := "WHERE "
 	for _, group := range where {
-		var (
-			emptyGroupItemCount = 0 // variable to determine empty/unspecified fieldName/Op/Value
-			groupItemCount      = 0 // valid gItem count, i.e. group item with valid name and value
-		)
-		groupItemsLen := len(group.GroupItems) // total items in a group
 		// check groupItems length, if 0 continue to the next group
-		if groupItemsLen < 1 {
-			emptyGroupCount += 1
+		if len(group.GroupItems) < 1 {
 			continue
 		}
-		// count valid group, i.e. group with groupItems
-		groupCount += 1
+		// valid group-item-scripts and the group-item-operators to the next item
+		var (
+			itemScripts []string
+			itemLinkOps []string
+		)
 		// sort group items by gItem/fieldOrder (ASC)
 		gItems := group.GroupItems
 		sort.SliceStable(gItems, func(i, j int) bool {
 			return gItems[i].GroupItemOrder < gItems[j].GroupItemOrder
 		})
 		// compute the group-items query/script
-		gItemQuery := "("
 		for _, gItem := range gItems {
 			// check gItem's fieldName, fieldOperator and fieldValue
 			fieldName := ""
@@ -61,14 +72,14 @@ func ComputeWhereQuery(where types.QueryParamType) (string, error) {
 
 			// ensure that len(gItem.GroupItem) == 1
 			if len(gItem.GroupItem) != 1 {
-				return "", errors.New(fmt.Sprintf("Only 1 field-name criteria is expected for each group-item"))
+				return types.WhereQueryResponseType{}, errors.New(fmt.Sprintf("Only 1 field-name criteria is expected for each group-item"))
 			}
 
 			for fName, opVal := range gItem.GroupItem {
 				fieldName = fName
 				// ensure that len(opVal) == 1
 				if len(opVal) != 1 {
-					return "", errors.New(fmt.Sprintf("Only 1 operator-value criteria is expected for a field-name: %v", fieldName))
+					return types.WhereQueryResponseType{}, errors.New(fmt.Sprintf("Only 1 operator-value criteria is expected for a field-name: %v", fieldName))
 				}
 				for fOp, val := range opVal {
 					fieldOperator = fOp
@@ -77,818 +88,100 @@ func ComputeWhereQuery(where types.QueryParamType) (string, error) {
 			}
 			if fieldName == "" || fieldOperator == "" || fieldValue == nil {
 				// skip missing field/continue to the next gItem, or return error?
-				emptyGroupItemCount += 1
 				continue
 				//return "", errors.New("field-name, operator and/or value are required")
 			}
-			// count valid gItem
-			groupItemCount += 1
+			// field-names are composed into the script, values are passed as placeholder-values only
+			fieldScript := fieldName
+			if fieldExprs != nil {
+				fieldExpr, ok := fieldExprs[fieldName]
+				if !ok {
+					return types.WhereQueryResponseType{}, errors.New(fmt.Sprintf("Invalid or unknown field-name: %v", fieldName))
+				}
+				fieldScript = fieldExpr
+			} else if !IsFieldName(fieldName) {
+				return types.WhereQueryResponseType{}, errors.New(fmt.Sprintf("Invalid field-name: %v", fieldName))
+			}
+			// next placeholder position
+			placeholder := fmt.Sprintf("$%v", fieldLength+len(fieldValues)+1)
 			switch strings.ToLower(fieldOperator) {
-			case strings.ToLower(operators.Equals):
-				switch fieldValue.(type) {
-				case time.Time:
-					if fVal, ok := fieldValue.(time.Time); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						currentFieldValue := "'" + fVal.Format("2006-01-02 15:04:05.000000") + "'"
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, currentFieldValue)
-					}
-				case string:
-					if fVal, ok := fieldValue.(string); !ok {
-						return "", errors.New(fmt.Sprintf("field_name:%v | field_value:%v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, "'"+fVal+"'")
-					}
-				case bool:
-					if fVal, ok := fieldValue.(bool); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case int8:
-					if fVal, ok := fieldValue.(int8); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case int16:
-					if fVal, ok := fieldValue.(int16); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case int32:
-					if fVal, ok := fieldValue.(int32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case int64:
-					if fVal, ok := fieldValue.(int64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case int:
-					if fVal, ok := fieldValue.(int); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case uint8:
-					if fVal, ok := fieldValue.(uint8); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case uint16:
-					if fVal, ok := fieldValue.(uint16); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case uint32:
-					if fVal, ok := fieldValue.(uint32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case uint64:
-					if fVal, ok := fieldValue.(uint64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case uint:
-					if fVal, ok := fieldValue.(uint); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case float32:
-					if fVal, ok := fieldValue.(float32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case float64:
-					if fVal, ok := fieldValue.(float64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case []string:
-					if fVal, ok := fieldValue.([]string); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case []int:
-					if fVal, ok := fieldValue.([]int); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case []float64:
-					if fVal, ok := fieldValue.([]float64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				case []struct{}:
-					if fVal, ok := fieldValue.([]struct{}); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v=%v", fieldName, fVal)
-					}
-				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
-				}
-			case strings.ToLower(operators.NotEquals):
-				switch fieldValue.(type) {
-				case time.Time:
-					if fVal, ok := fieldValue.(time.Time); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						currentFieldValue := "'" + fVal.Format("2006-01-02 15:04:05.000000") + "'"
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, currentFieldValue)
-					}
-				case string:
-					if fVal, ok := fieldValue.(string); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, "'"+fVal+"'")
-					}
-				case bool:
-					if fVal, ok := fieldValue.(bool); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case int8:
-					if fVal, ok := fieldValue.(int8); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case int16:
-					if fVal, ok := fieldValue.(int16); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case int32:
-					if fVal, ok := fieldValue.(int32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case int64:
-					if fVal, ok := fieldValue.(int64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case int:
-					if fVal, ok := fieldValue.(int); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case uint8:
-					if fVal, ok := fieldValue.(uint8); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case uint16:
-					if fVal, ok := fieldValue.(uint16); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case uint32:
-					if fVal, ok := fieldValue.(uint32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case uint64:
-					if fVal, ok := fieldValue.(uint64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case uint:
-					if fVal, ok := fieldValue.(uint); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case float32:
-					if fVal, ok := fieldValue.(float32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case float64:
-					if fVal, ok := fieldValue.(float64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case []string:
-					if fVal, ok := fieldValue.([]string); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case []int:
-					if fVal, ok := fieldValue.([]int); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				case []struct{}:
-					if fVal, ok := fieldValue.([]struct{}); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<>%v", fieldName, fVal)
-					}
-				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
-				}
-			case strings.ToLower(operators.LessThan):
-				switch fieldValue.(type) {
-				case time.Time:
-					if fVal, ok := fieldValue.(time.Time); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						currentFieldValue := "'" + fVal.Format("2006-01-02 15:04:05.000000") + "'"
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, currentFieldValue)
-					}
-				case int8:
-					if fVal, ok := fieldValue.(int8); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, fVal)
-					}
-				case int16:
-					if fVal, ok := fieldValue.(int16); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, fVal)
-					}
-				case int32:
-					if fVal, ok := fieldValue.(int32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, fVal)
-					}
-				case int64:
-					if fVal, ok := fieldValue.(int64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, fVal)
-					}
-				case int:
-					if fVal, ok := fieldValue.(int); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, fVal)
-					}
-				case uint8:
-					if fVal, ok := fieldValue.(uint8); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, fVal)
-					}
-				case uint16:
-					if fVal, ok := fieldValue.(uint16); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, fVal)
-					}
-				case uint32:
-					if fVal, ok := fieldValue.(uint32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, fVal)
-					}
-				case uint64:
-					if fVal, ok := fieldValue.(uint64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, fVal)
-					}
-				case uint:
-					if fVal, ok := fieldValue.(uint); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, fVal)
-					}
-				case float32:
-					if fVal, ok := fieldValue.(float32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, fVal)
-					}
-				case float64:
-					if fVal, ok := fieldValue.(float64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<%v", fieldName, fVal)
-					}
-				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value: %v", fieldName, fieldValue))
-				}
-			case strings.ToLower(operators.LessThanOrEquals):
-				switch fieldValue.(type) {
-				case time.Time:
-					if fVal, ok := fieldValue.(time.Time); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						currentFieldValue := "'" + fVal.Format("2006-01-02 15:04:05.000000") + "'"
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, currentFieldValue)
-					}
-				case int8:
-					if fVal, ok := fieldValue.(int8); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, fVal)
-					}
-				case int16:
-					if fVal, ok := fieldValue.(int16); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, fVal)
-					}
-				case int32:
-					if fVal, ok := fieldValue.(int32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, fVal)
-					}
-				case int64:
-					if fVal, ok := fieldValue.(int64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, fVal)
-					}
-				case int:
-					if fVal, ok := fieldValue.(int); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, fVal)
-					}
-				case uint8:
-					if fVal, ok := fieldValue.(uint8); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, fVal)
-					}
-				case uint16:
-					if fVal, ok := fieldValue.(uint16); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, fVal)
-					}
-				case uint32:
-					if fVal, ok := fieldValue.(uint32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, fVal)
-					}
-				case uint64:
-					if fVal, ok := fieldValue.(uint64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, fVal)
-					}
-				case uint:
-					if fVal, ok := fieldValue.(uint); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, fVal)
-					}
-				case float32:
-					if fVal, ok := fieldValue.(float32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, fVal)
-					}
-				case float64:
-					if fVal, ok := fieldValue.(float64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v<=%v", fieldName, fVal)
-					}
-				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field[%v], type for field-value %v", fieldName, fieldValue))
-				}
-				groupItemOps := []string{"and", "or"}
-				groupItemOp := gItem.GroupItemOp
-				if groupItemOp == "" || !ArrayStringContains(groupItemOps, strings.ToLower(groupItemOp)) {
-					groupItemOp = groupOperators.AND // use GroupOpTypes.AND as default operator
-				}
-				if groupItemsLen > 1 && groupItemCount < (groupItemsLen-emptyGroupItemCount) {
-					gItemQuery = gItemQuery + " " + strings.ToUpper(gItem.GroupItemOp) + " "
-				}
-			case strings.ToLower(operators.GreaterThan):
+			case strings.ToLower(operators.Equals), strings.ToLower(operators.NotEquals):
 				switch fieldValue.(type) {
-				case time.Time:
-					if fVal, ok := fieldValue.(time.Time); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						currentFieldValue := "'" + fVal.Format("2006-01-02 15:04:05.000000") + "'"
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, currentFieldValue)
-					}
-				case int8:
-					if fVal, ok := fieldValue.(int8); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, fVal)
-					}
-				case int16:
-					if fVal, ok := fieldValue.(int16); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, fVal)
-					}
-				case int32:
-					if fVal, ok := fieldValue.(int32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, fVal)
-					}
-				case int64:
-					if fVal, ok := fieldValue.(int64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, fVal)
-					}
-				case int:
-					if fVal, ok := fieldValue.(int); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, fVal)
-					}
-				case uint8:
-					if fVal, ok := fieldValue.(uint8); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, fVal)
-					}
-				case uint16:
-					if fVal, ok := fieldValue.(uint16); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, fVal)
-					}
-				case uint32:
-					if fVal, ok := fieldValue.(uint32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, fVal)
-					}
-				case uint64:
-					if fVal, ok := fieldValue.(uint64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, fVal)
-					}
-				case uint:
-					if fVal, ok := fieldValue.(uint); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, fVal)
-					}
-				case float32:
-					if fVal, ok := fieldValue.(float32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, fVal)
-					}
-				case float64:
-					if fVal, ok := fieldValue.(float64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>%v", fieldName, fVal)
-					}
+				case time.Time, string, bool, int8, int16, int32, int64, int, uint8, uint16, uint32, uint64, uint,
+					float32, float64, []string, []int, []float64, []struct{}:
+					sqlOp := "="
+					if strings.ToLower(fieldOperator) == strings.ToLower(operators.NotEquals) {
+						sqlOp = "<>"
+					}
+					itemScripts = append(itemScripts, fmt.Sprintf("%v%v%v", fieldScript, sqlOp, placeholder))
+					fieldValues = append(fieldValues, fieldValue)
 				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
+					return types.WhereQueryResponseType{}, errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
 				}
-			case strings.ToLower(operators.GreaterThanOrEquals):
+			case strings.ToLower(operators.LessThan), strings.ToLower(operators.LessThanOrEquals),
+				strings.ToLower(operators.GreaterThan), strings.ToLower(operators.GreaterThanOrEquals):
 				switch fieldValue.(type) {
-				case time.Time:
-					if fVal, ok := fieldValue.(time.Time); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						currentFieldValue := "'" + fVal.Format("2006-01-02 15:04:05.000000") + "'"
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, currentFieldValue)
-					}
-				case int8:
-					if fVal, ok := fieldValue.(int8); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, fVal)
-					}
-				case int16:
-					if fVal, ok := fieldValue.(int16); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, fVal)
-					}
-				case int32:
-					if fVal, ok := fieldValue.(int32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, fVal)
-					}
-				case int64:
-					if fVal, ok := fieldValue.(int64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, fVal)
-					}
-				case int:
-					if fVal, ok := fieldValue.(int); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, fVal)
-					}
-				case uint8:
-					if fVal, ok := fieldValue.(uint8); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, fVal)
-					}
-				case uint16:
-					if fVal, ok := fieldValue.(uint16); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, fVal)
-					}
-				case uint32:
-					if fVal, ok := fieldValue.(uint32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, fVal)
-					}
-				case uint64:
-					if fVal, ok := fieldValue.(uint64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, fVal)
-					}
-				case uint:
-					if fVal, ok := fieldValue.(uint); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, fVal)
-					}
-				case float32:
-					if fVal, ok := fieldValue.(float32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, fVal)
-					}
-				case float64:
-					if fVal, ok := fieldValue.(float64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fmt.Sprintf("%v>=%v", fieldName, fVal)
-					}
+				case time.Time, int8, int16, int32, int64, int, uint8, uint16, uint32, uint64, uint, float32, float64:
+					var sqlOp string
+					switch strings.ToLower(fieldOperator) {
+					case strings.ToLower(operators.LessThan):
+						sqlOp = "<"
+					case strings.ToLower(operators.LessThanOrEquals):
+						sqlOp = "<="
+					case strings.ToLower(operators.GreaterThan):
+						sqlOp = ">"
+					default:
+						sqlOp = ">="
+					}
+					itemScripts = append(itemScripts, fmt.Sprintf("%v%v%v", fieldScript, sqlOp, placeholder))
+					fieldValues = append(fieldValues, fieldValue)
 				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
+					return types.WhereQueryResponseType{}, errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
 				}
-			case strings.ToLower(operators.In):
+			case strings.ToLower(operators.In), strings.ToLower(operators.NotIn):
+				// the in-values are bound as a single array-parameter
 				switch fieldValue.(type) {
-				case []string:
-					if fVal, ok := fieldValue.([]string); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						inValues := ""
-						idLen := len(fVal)
-						for idCount, id := range fVal {
-							inValues += "'" + id + "'"
-							if idLen > 1 && idCount < idLen-1 {
-								inValues += ", "
-							}
-						}
-						gItemQuery += fmt.Sprintf("%v IN (%v)", fieldName, inValues)
-					}
-				case []bool:
-					if fVal, ok := fieldValue.([]bool); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						inValues := "("
-						fValLen := len(fVal)
-						for i, v := range fVal {
-							inValues += fmt.Sprintf("%v", v)
-							if fValLen > 1 && i < fValLen-1 {
-								inValues += inValues + ", "
-							}
-						}
-						inValues += ")"
-						gItemQuery += fmt.Sprintf("%v IN %v", fieldName, inValues)
-					}
-				case []int:
-					if fVal, ok := fieldValue.([]int); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
+				case []string, []bool, []int, []int32, []int64, []float32, []float64:
+					if strings.ToLower(fieldOperator) == strings.ToLower(operators.In) {
+						itemScripts = append(itemScripts, fmt.Sprintf("%v = ANY(%v)", fieldScript, placeholder))
 					} else {
-						inValues := "("
-						fValLen := len(fVal)
-						for i, v := range fVal {
-							inValues += fmt.Sprintf("%v", v)
-							if fValLen > 1 && i < fValLen-1 {
-								inValues += inValues + ", "
-							}
-						}
-						inValues += ")"
-						gItemQuery += fmt.Sprintf("%v IN %v", fieldName, inValues)
-					}
-				case []float32:
-					if fVal, ok := fieldValue.([]float32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						inValues := "("
-						fValLen := len(fVal)
-						for i, v := range fVal {
-							inValues += fmt.Sprintf("%v", v)
-							if fValLen > 1 && i < fValLen-1 {
-								inValues += inValues + ", "
-							}
-						}
-						inValues += ")"
-						gItemQuery += fmt.Sprintf("%v IN %v", fieldName, inValues)
-					}
-				case []float64:
-					if fVal, ok := fieldValue.([]float64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						inValues := "("
-						fValLen := len(fVal)
-						for i, v := range fVal {
-							inValues += fmt.Sprintf("%v", v)
-							if fValLen > 1 && i < fValLen-1 {
-								inValues += inValues + ", "
-							}
-						}
-						inValues += ")"
-						gItemQuery += fmt.Sprintf("%v IN %v", fieldName, inValues)
+						itemScripts = append(itemScripts, fmt.Sprintf("%v <> ALL(%v)", fieldScript, placeholder))
 					}
+					fieldValues = append(fieldValues, fieldValue)
 				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
-				}
-			case strings.ToLower(operators.NotIn):
-				switch fieldValue.(type) {
-				case []string:
-					if fVal, ok := fieldValue.([]string); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						inValues := ""
-						idLen := len(fVal)
-						for idCount, id := range fVal {
-							inValues += "'" + id + "'"
-							if idLen > 1 && idCount < idLen-1 {
-								inValues += ", "
-							}
-						}
-						gItemQuery += fmt.Sprintf("%v NOT IN (%v)", fieldName, inValues)
-					}
-				case []bool:
-					if fVal, ok := fieldValue.([]bool); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						inValues := "("
-						fValLen := len(fVal)
-						for i, v := range fVal {
-							inValues += fmt.Sprintf("%v", v)
-							if fValLen > 1 && i < fValLen-1 {
-								inValues += inValues + ", "
-							}
-						}
-						inValues += ")"
-						gItemQuery += fmt.Sprintf("%v NOT IN %v", fieldName, inValues)
-					}
-				case []int:
-					if fVal, ok := fieldValue.([]int); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						inValues := "("
-						fValLen := len(fVal)
-						for i, v := range fVal {
-							inValues += fmt.Sprintf("%v", v)
-							if fValLen > 1 && i < fValLen-1 {
-								inValues += inValues + ", "
-							}
-						}
-						inValues += ")"
-						gItemQuery += fmt.Sprintf("%v NOT IN %v", fieldName, inValues)
-					}
-				case []float32:
-					if fVal, ok := fieldValue.([]float32); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						inValues := "("
-						fValLen := len(fVal)
-						for i, v := range fVal {
-							inValues += fmt.Sprintf("%v", v)
-							if fValLen > 1 && i < fValLen-1 {
-								inValues += inValues + ", "
-							}
-						}
-						inValues += ")"
-						gItemQuery += fmt.Sprintf("%v NOT IN %v", fieldName, inValues)
-					}
-				case []float64:
-					if fVal, ok := fieldValue.([]float64); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						inValues := "("
-						fValLen := len(fVal)
-						for i, v := range fVal {
-							inValues += fmt.Sprintf("%v", v)
-							if fValLen > 1 && i < fValLen-1 {
-								inValues += inValues + ", "
-							}
-						}
-						inValues += ")"
-						gItemQuery += fmt.Sprintf("%v NOT IN %v", fieldName, inValues)
-					}
+					return types.WhereQueryResponseType{}, errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
+				}
+			case strings.ToLower(operators.StartsWith), strings.ToLower(operators.EndsWith),
+				strings.ToLower(operators.NotStartsWith), strings.ToLower(operators.NotEndsWith),
+				strings.ToLower(operators.Includes), strings.ToLower(operators.NotIncludes):
+				fVal, ok := fieldValue.(string)
+				if !ok {
+					return types.WhereQueryResponseType{}, errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
+				}
+				// the like-pattern is computed from the escaped field-value
+				likeValue := EscapeLikeValue(fVal)
+				sqlOp := "LIKE"
+				switch strings.ToLower(fieldOperator) {
+				case strings.ToLower(operators.StartsWith):
+					likeValue = likeValue + "%"
+				case strings.ToLower(operators.NotStartsWith):
+					likeValue = likeValue + "%"
+					sqlOp = "NOT LIKE"
+				case strings.ToLower(operators.EndsWith):
+					likeValue = "%" + likeValue
+				case strings.ToLower(operators.NotEndsWith):
+					likeValue = "%" + likeValue
+					sqlOp = "NOT LIKE"
+				case strings.ToLower(operators.Includes):
+					likeValue = "%" + likeValue + "%"
 				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
-				}
-			case strings.ToLower(operators.StartsWith):
-				switch fieldValue.(type) {
-				case string:
-					if fVal, ok := fieldValue.(string); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						//gItemQuery += fmt.Sprintf("%v LIKE %%v", fieldName, fVal)
-						gItemQuery += fieldName + " LIKE " + fVal + "%"
-					}
-				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
-				}
-			case strings.ToLower(operators.EndsWith):
-				switch fieldValue.(type) {
-				case string:
-					if fVal, ok := fieldValue.(string); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fieldName + " LIKE %" + fVal
-					}
-				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
-				}
-			case strings.ToLower(operators.NotStartsWith):
-				switch fieldValue.(type) {
-				case string:
-					if fVal, ok := fieldValue.(string); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fieldName + " NOT LIKE " + fVal + "%"
-					}
-				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
-				}
-			case strings.ToLower(operators.NotEndsWith):
-				switch fieldValue.(type) {
-				case string:
-					if fVal, ok := fieldValue.(string); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fieldName + " NOT LIKE %" + fVal
-					}
-				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
-				}
-			case strings.ToLower(operators.Includes):
-				switch fieldValue.(type) {
-				case string:
-					if fVal, ok := fieldValue.(string); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fieldName + " LIKE %" + fVal + "%"
-					}
-				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
-				}
-			case strings.ToLower(operators.NotIncludes):
-				switch fieldValue.(type) {
-				case string:
-					if fVal, ok := fieldValue.(string); !ok {
-						return "", errors.New(fmt.Sprintf("field_name: %v | field_value: %v error: ", fieldName, fieldValue))
-					} else {
-						gItemQuery += fieldName + " NOT LIKE %" + fVal + "%"
-					}
-				default:
-					return "", errors.New(fmt.Sprintf("Unsupported field-name[%v] type for field-value %v", fieldName, fieldValue))
+					likeValue = "%" + likeValue + "%"
+					sqlOp = "NOT LIKE"
 				}
+				itemScripts = append(itemScripts, fmt.Sprintf("%v %v %v", fieldScript, sqlOp, placeholder))
+				fieldValues = append(fieldValues, likeValue)
 			default:
-				return "", errors.New(fmt.Sprintf("Unknown or unsupported field(%v) operator: %v", fieldName, fieldOperator))
+				return types.WhereQueryResponseType{}, errors.New(fmt.Sprintf("Unknown or unsupported field(%v) operator: %v", fieldName, fieldOperator))
 			}
 			//validate acceptable groupItemLinkOperators (and || or)
 			gItemLinkOp := gItem.GroupItemOp
@@ -896,35 +189,59 @@ func ComputeWhereQuery(where types.QueryParamType) (string, error) {
 			if gItemLinkOp == "" || !ArrayStringContains(gItemLinkOps, strings.ToLower(gItemLinkOp)) {
 				gItemLinkOp = groupOperators.AND // default operator
 			}
-			// add gItemLinkOp, if groupItemsLen > 1 && groupItemCount < (groupItemsLen-emptyGroupItemCount)
-			if groupItemsLen > 1 && groupItemCount < (groupItemsLen-emptyGroupItemCount) {
-				gItemQuery += " " + strings.ToUpper(gItemLinkOp) + " "
-			}
+			itemLinkOps = append(itemLinkOps, strings.ToUpper(gItemLinkOp))
 		}
 		// continue to the next group iteration, if fieldItems is empty for the current group
-		if emptyGroupItemCount == groupItemsLen {
+		if len(itemScripts) < 1 {
 			continue
 		}
-		// add closing bracket to complete the group-items query/script
+		// compose the group-items query/script, the last item's link-operator is ignored
+		gItemQuery := "("
+		for i, itemScript := range itemScripts {
+			gItemQuery += itemScript
+			if i < len(itemScripts)-1 {
+				gItemQuery += " " + itemLinkOps[i] + " "
+			}
+		}
 		gItemQuery += ")"
-		// add group-item-query to the group/where-script, append in sequence by groupOrder
-		whereQuery += " " + gItemQuery
+		groupScripts = append(groupScripts, gItemQuery)
 		//validate acceptable groupLinkOperators (and || or)
 		grpLinkOp := group.GroupLinkOp
 		groupLnOps := []string{"and", "or"}
 		if grpLinkOp == "" || !ArrayStringContains(groupLnOps, strings.ToLower(grpLinkOp)) {
 			grpLinkOp = groupOperators.AND // default operator
 		}
-		// add groupLinkOp, if groupsLen > 1 && groupCount < (groupsLen-emptyGroupCount)
-		if groupsLen > 1 && groupCount < (groupsLen-emptyGroupCount) {
-			whereQuery += " " + strings.ToUpper(grpLinkOp) + " "
-		}
-		// check WHERE script contains at least one condition, otherwise raise an exception
-		if emptyGroupCount == groupsLen {
-			return "", errors.New("no valid where condition specified")
+		groupLinkOps = append(groupLinkOps, strings.ToUpper(grpLinkOp))
+	}
+	// check WHERE script contains at least one condition, otherwise raise an exception
+	if len(groupScripts) < 1 {
+		return types.WhereQueryResponseType{}, errors.New("no valid where condition specified")
+	}
+	// add group-scripts to the where-script, in sequence by groupOrder, the last group's link-operator is ignored
+	whereQuery := keyword
+	for i, groupScript := range groupScripts {
+		whereQuery += " " + groupScript
+		if i < len(groupScripts)-1 {
+			whereQuery += " " + groupLinkOps[i]
 		}
 	}
 
-	// if all went well, return valid where script
-	return whereQuery, nil
+	// if all went well, return valid where script and placeholder-values
+	return types.WhereQueryResponseType{
+		WhereQuery:  whereQuery,
+		FieldValues: fieldValues,
+	}, nil
+}
+
+// ComputeWhereQueryByIds function computes the where-condition for the specified record-ids, as a single
+// array placeholder-value (id = ANY($n)), numbered from fieldLength+1
+func ComputeWhereQueryByIds(recordIds []string, fieldLength int) (types.WhereQueryResponseType, error) {
+	idsParam, err := ComputeIdsParam(recordIds)
+	if err != nil {
+		return types.WhereQueryResponseType{}, err
+	}
+	return types.WhereQueryResponseType{
+		WhereQuery:  fmt.Sprintf("WHERE id = ANY($%v)", fieldLength+1),
+		FieldValues: []interface{}{idsParam},
+	}, nil
 }
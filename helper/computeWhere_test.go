@@ -0,0 +1,129 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-07 | @Updated: 2021-01-07
+// @Company: mConnect.biz | @License: MIT
+// @Description: where-query (placeholder-values) test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mctest"
+	"testing"
+)
+
+var whereParams = types.QueryParamType{
+	types.QueryGroupType{
+		GroupName:   "name_age",
+		GroupOrder:  2,
+		GroupLinkOp: "or",
+		GroupItems: []types.QueryItemType{
+			{
+				GroupItem:      map[string]map[string]interface{}{"name": {"eq": "O'Brien"}},
+				GroupItemOrder: 1,
+				GroupItemOp:    "or",
+			},
+			{
+				GroupItem:      map[string]map[string]interface{}{"age": {"gte": 18}},
+				GroupItemOrder: 2,
+			},
+		},
+	},
+	types.QueryGroupType{
+		GroupName:   "id_description",
+		GroupOrder:  1,
+		GroupLinkOp: "and",
+		GroupItems: []types.QueryItemType{
+			{
+				GroupItem:      map[string]map[string]interface{}{"id": {"in": []string{"6900d9f9-2ceb-450f-9a9e-527eb66c962f", "122d0f0e-3111-41a5-9103-24fa81004550"}}},
+				GroupItemOrder: 1,
+				GroupItemOp:    "and",
+			},
+			{
+				GroupItem:      map[string]map[string]interface{}{"description": {"includes": "50%_off"}},
+				GroupItemOrder: 2,
+			},
+		},
+	},
+}
+
+func TestComputeWhereQuery(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute where-query with placeholder-values, by group and item order:",
+		TestFunc: func() {
+			res, err := ComputeWhereQuery(whereParams, 0)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.WhereQuery, "WHERE (id = ANY($1) AND description LIKE $2) AND (name=$3 OR age>=$4)", "where-query should match the placeholder script")
+			mctest.AssertEquals(t, len(res.FieldValues), 4, "field-values count should be: 4")
+			mctest.AssertEquals(t, res.FieldValues[1], `%50\%\_off%`, "like-value should be escaped")
+			mctest.AssertEquals(t, res.FieldValues[2], "O'Brien", "field-value should be passed as-is")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should number the placeholders from the specified field-length:",
+		TestFunc: func() {
+			res, err := ComputeWhereQuery(whereParams, 3)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.WhereQuery, "WHERE (id = ANY($4) AND description LIKE $5) AND (name=$6 OR age>=$7)", "where-query placeholders should start from $4")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return an error for an invalid field-name:",
+		TestFunc: func() {
+			invalidParams := types.QueryParamType{
+				types.QueryGroupType{
+					GroupName:  "invalid",
+					GroupOrder: 1,
+					GroupItems: []types.QueryItemType{
+						{
+							GroupItem:      map[string]map[string]interface{}{"name='x' OR 1=1 --": {"eq": "abc"}},
+							GroupItemOrder: 1,
+						},
+					},
+				},
+			}
+			_, err := ComputeWhereQuery(invalidParams, 0)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return an error for empty where-params:",
+		TestFunc: func() {
+			_, err := ComputeWhereQuery(types.QueryParamType{}, 0)
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+
+	mctest.PostTestResult()
+}
+
+func TestComputeWhereQueryByIds(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute where-query by ids as a single array placeholder-value:",
+		TestFunc: func() {
+			res, err := ComputeWhereQueryByIds([]string{"6900D9F9-2CEB-450F-9A9E-527EB66C962F", "122d0f0e-3111-41a5-9103-24fa81004550"}, 2)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res.WhereQuery, "WHERE id = ANY($3)", "where-query should be: WHERE id = ANY($3)")
+			mctest.AssertStrictEquals(t, res.FieldValues, []interface{}{[]string{"6900D9F9-2CEB-450F-9A9E-527EB66C962F", "122d0f0e-3111-41a5-9103-24fa81004550"}}, "uuid-ids should be the string values, cast by the DB")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should compute the numeric-looking ids placeholder-value, as the string values:",
+		TestFunc: func() {
+			idsParam, err := ComputeIdsParam([]string{"10", "20"})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertStrictEquals(t, idsParam, []string{"10", "20"}, "ids-param should be of type []string")
+			idsParam, _ = ComputeIdsParam([]string{"007", "123"})
+			mctest.AssertStrictEquals(t, idsParam, []string{"007", "123"}, "numeric-looking text-ids should keep the string values")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should return an error for empty record-ids:",
+		TestFunc: func() {
+			_, err := ComputeIdsParam([]string{})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+			_, err = ComputeIdsParam([]string{"abc", ""})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -0,0 +1,191 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-17 | @Updated: 2021-01-17
+// @Company: mConnect.biz | @License: MIT
+// @Description: generate Go structs, with json/mcorm tags, from the model or the live table
+
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// information_schema udt-names to datatypes, for reverse-engineering the live table
+var udtDataTypes = map[string]string{
+	"varchar":     datatypes.String,
+	"bpchar":      datatypes.String,
+	"text":        datatypes.Text,
+	"uuid":        datatypes.UUID,
+	"int2":        datatypes.Integer,
+	"int4":        datatypes.Integer,
+	"int8":        datatypes.BigInt,
+	"numeric":     datatypes.Decimal,
+	"float4":      datatypes.Float32,
+	"float8":      datatypes.Float64,
+	"bool":        datatypes.Boolean,
+	"timestamptz": datatypes.DateTime,
+	"timestamp":   datatypes.TimeStamp,
+	"date":        datatypes.Date,
+	"time":        datatypes.Time,
+	"inet":        datatypes.IP,
+	"json":        datatypes.JSON,
+	"jsonb":       datatypes.JSON,
+	"_text":       datatypes.ArrayOfString,
+	"_varchar":    datatypes.ArrayOfString,
+	"_float8":     datatypes.ArrayOfNumber,
+	"_bool":       datatypes.ArrayOfBoolean,
+}
+
+// ComputeStructFieldType computes the Go type of the field-description data-type. The nullable fields of
+// scalar types are pointers, e.g. *string
+func ComputeStructFieldType(fieldDesc types.FieldDescType) (string, error) {
+	var fieldType string
+	switch fieldDesc.FieldType {
+	case datatypes.Integer, datatypes.Positive, datatypes.Natural, datatypes.Negative, datatypes.Port:
+		fieldType = "int"
+	case datatypes.BigInt:
+		fieldType = "int64"
+	case datatypes.Float32:
+		fieldType = "float32"
+	case datatypes.Number, datatypes.Decimal, datatypes.Float, datatypes.Float64, datatypes.BigFloat,
+		datatypes.Latitude, datatypes.Longitude:
+		fieldType = "float64"
+	case datatypes.Boolean:
+		fieldType = "bool"
+	case datatypes.DateTime, datatypes.Date, datatypes.Time, datatypes.TimeStamp, datatypes.TimeStampZ:
+		fieldType = "time.Time"
+	case datatypes.JSON, datatypes.Object, datatypes.Map:
+		return "map[string]interface{}", nil
+	case datatypes.Array, datatypes.Set:
+		return "[]interface{}", nil
+	case datatypes.ArrayOfString:
+		return "[]string", nil
+	case datatypes.ArrayOfNumber:
+		return "[]float64", nil
+	case datatypes.ArrayOfBoolean:
+		return "[]bool", nil
+	case datatypes.ArrayOfStruct, datatypes.ArrayOfMap:
+		return "[]map[string]interface{}", nil
+	case datatypes.ArrayOfArray:
+		return "[][]interface{}", nil
+	default:
+		// string-based data-types
+		if _, err := ComputeFieldType(fieldDesc); err != nil {
+			return "", err
+		}
+		fieldType = "string"
+	}
+	if fieldDesc.AllowNull {
+		return "*" + fieldType, nil
+	}
+	return fieldType, nil
+}
+
+// ComputeStructName computes the struct-name of the table-name, e.g. app.user_roles => UserRoles
+func ComputeStructName(tableName string) string {
+	tableNames := strings.Split(tableName, ".")
+	return ToCamelCase(tableNames[len(tableNames)-1], true)
+}
+
+// GenerateStruct generates the Go struct of the model, with json (camelCase) and mcorm (table-field) tags, named
+// by the table-name, if structName is not specified
+func GenerateStruct(model types.ModelType, structName string) (string, error) {
+	if structName == "" {
+		structName = ComputeStructName(model.TableName)
+	}
+	if !IsFieldName(structName) {
+		return "", errors.New(fmt.Sprintf("Invalid struct-name: %v", structName))
+	}
+	modelFields, err := ComputeModelFields(model)
+	if err != nil {
+		return "", err
+	}
+	var structFields []string
+	for _, field := range modelFields {
+		fieldType, err := ComputeStructFieldType(field.FieldDesc)
+		if err != nil {
+			return "", errors.New(fmt.Sprintf("field [%v]: %v", field.FieldName, err.Error()))
+		}
+		structFields = append(structFields, fmt.Sprintf("%v %v `json:\"%v\" mcorm:\"%v\"`", ToCamelCase(field.FieldName, true), fieldType, ToCamelCase(field.FieldName, false), field.FieldName))
+	}
+	structSource := fmt.Sprintf("// %v describes the %v table-record\ntype %v struct {\n%v\n}\n", structName, model.TableName, structName, strings.Join(structFields, "\n"))
+	formatted, err := format.Source([]byte(structSource))
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Error formatting the struct [%v]: %v", structName, err.Error()))
+	}
+	return string(formatted), nil
+}
+
+// GenerateStructFile generates the Go source-file of the models' structs, for the package-name
+func GenerateStructFile(packageName string, models []types.ModelType) (string, error) {
+	var structs []string
+	for _, model := range models {
+		structSource, err := GenerateStruct(model, "")
+		if err != nil {
+			return "", errors.New(fmt.Sprintf("table [%v]: %v", model.TableName, err.Error()))
+		}
+		structs = append(structs, structSource)
+	}
+	fileSource := fmt.Sprintf("// Code generated by mcorm gen struct. DO NOT EDIT.\n\npackage %v\n\n", packageName)
+	if strings.Contains(strings.Join(structs, ""), "time.Time") {
+		fileSource += "import \"time\"\n\n"
+	}
+	fileSource += strings.Join(structs, "\n")
+	formatted, err := format.Source([]byte(fileSource))
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Error formatting the structs file: %v", err.Error()))
+	}
+	return string(formatted), nil
+}
+
+// ComputeTableModel reverse-engineers the model (record-description) of the live table-columns, keyed by the
+// camelCase field-names that map back to the column-names
+func ComputeTableModel(tableName string, tableColumns map[string]TableColumnType) (types.ModelType, error) {
+	if len(tableColumns) < 1 {
+		return types.ModelType{}, errors.New(fmt.Sprintf("table [%v] does not exist", tableName))
+	}
+	var columnNames []string
+	for columnName := range tableColumns {
+		columnNames = append(columnNames, columnName)
+	}
+	sort.Strings(columnNames)
+	recordDesc := types.RecordDescType{}
+	for _, columnName := range columnNames {
+		column := tableColumns[columnName]
+		fieldType, ok := udtDataTypes[column.UdtName]
+		if !ok {
+			// unknown column-types, as text
+			fieldType = datatypes.Text
+		}
+		// the camelCase field-name maps to the table-field (snake_case), otherwise the column-name, e.g. address_1
+		fieldName := ToCamelCase(columnName, false)
+		if ToSnakeCase(fieldName) != columnName {
+			fieldName = columnName
+		}
+		recordDesc[fieldName] = types.FieldDescType{
+			FieldType:   fieldType,
+			FieldLength: column.MaxLength,
+			AllowNull:   column.IsNullable,
+			Unique:      column.UniqueConstraint != "",
+		}
+	}
+	return types.ModelType{TableName: tableName, RecordDesc: recordDesc}, nil
+}
+
+// GenerateTableStruct generates the Go struct of the live table, by reverse-engineering the table-columns
+func GenerateTableStruct(tableName string, appDb *pgxpool.Pool) (string, error) {
+	tableColumns, err := GetTableColumns(tableName, appDb)
+	if err != nil {
+		return "", err
+	}
+	model, err := ComputeTableModel(tableName, tableColumns)
+	if err != nil {
+		return "", err
+	}
+	return GenerateStruct(model, "")
+}
@@ -0,0 +1,71 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-17 | @Updated: 2021-01-17
+// @Company: mConnect.biz | @License: MIT
+// @Description: struct code-generator test cases
+
+package helper
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/datatypes"
+	"github.com/abbeymart/mctest"
+	"strings"
+	"testing"
+)
+
+func TestGenerateStruct(t *testing.T) {
+	mctest.McTest(mctest.OptionValue{
+		Name: "should generate the struct of the model, with json/mcorm tags and Go types:",
+		TestFunc: func() {
+			res, err := GenerateStruct(createTableModel, "")
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, res, "// Services describes the services table-record\n"+
+				"type Services struct {\n"+
+				"\tCategory  string    `json:\"category\" mcorm:\"category\"`\n"+
+				"\tCost      *float64  `json:\"cost\" mcorm:\"cost\"`\n"+
+				"\tName      string    `json:\"name\" mcorm:\"name\"`\n"+
+				"\tPriority  int       `json:\"priority\" mcorm:\"priority\"`\n"+
+				"\tCreatedAt time.Time `json:\"createdAt\" mcorm:\"created_at\"`\n"+
+				"\tUpdatedAt time.Time `json:\"updatedAt\" mcorm:\"updated_at\"`\n"+
+				"}\n", "struct should match the model")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should generate the struct file, from the reverse-engineered table-columns:",
+		TestFunc: func() {
+			model, err := ComputeTableModel("services", serviceTableColumns)
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, model.RecordDesc["createdAt"].FieldType, datatypes.DateTime, "created_at should be a datetime field")
+			mctest.AssertEquals(t, model.RecordDesc["name"].Unique, true, "name should be a unique field")
+			res, err := GenerateStructFile("models", []types.ModelType{model})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, strings.HasPrefix(res, "// Code generated by mcorm gen struct. DO NOT EDIT.\n\npackage models\n\nimport \"time\"\n"), true, "struct file should include the package and time import")
+			mctest.AssertEquals(t, strings.Contains(res, "\tName      string    `json:\"name\" mcorm:\"name\"`\n"), true, "struct file should include the name field")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should keep the column-name in the mcorm tag, for the digit-suffixed columns:",
+		TestFunc: func() {
+			model, err := ComputeTableModel("addresses", map[string]TableColumnType{
+				"address_1": {ColumnName: "address_1", UdtName: "varchar", MaxLength: 120},
+				"post_code": {ColumnName: "post_code", UdtName: "varchar", MaxLength: 20},
+			})
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			res, err := GenerateStruct(model, "")
+			mctest.AssertEquals(t, err, nil, "error-response should be: nil")
+			mctest.AssertEquals(t, strings.Contains(res, "\tAddress1 string `json:\"address1\" mcorm:\"address_1\"`\n"), true, "struct should include the address_1 column")
+			mctest.AssertEquals(t, strings.Contains(res, "\tPostCode string `json:\"postCode\" mcorm:\"post_code\"`\n"), true, "struct should include the post_code column")
+		},
+	})
+	mctest.McTest(mctest.OptionValue{
+		Name: "should convert the names to camelCase, and return error for unknown field-type:",
+		TestFunc: func() {
+			mctest.AssertEquals(t, ToCamelCase("app_id", true), "AppId", "should be PascalCase")
+			mctest.AssertEquals(t, ToCamelCase("createdAt", false), "createdAt", "should be camelCase")
+			mctest.AssertEquals(t, ComputeStructName("app.user_roles"), "UserRoles", "should be the struct-name of the table")
+			_, err := ComputeStructFieldType(types.FieldDescType{FieldType: datatypes.Unknown})
+			mctest.AssertNotEquals(t, err, nil, "error-response should not be: nil")
+		},
+	})
+
+	mctest.PostTestResult()
+}
@@ -10,10 +10,17 @@ import (
 	"fmt"
 	"github.com/abbeymart/mcorm/types"
 	"github.com/asaskevich/govalidator"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
 	"reflect"
+	"regexp"
 	"strings"
+	"unicode"
 )
 
+// fieldNamePattern permits plain (unquoted) table/column names only
+var fieldNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 type EmailUserNameType struct {
 	Email    string
 	Username string
@@ -67,10 +74,99 @@ func ArrayIntContains(arr []int, val int) bool {
 	return false
 }
 
+// IsFieldName validates the field/column name, prior to composing it into SQL scripts
+func IsFieldName(fieldName string) bool {
+	return fieldNamePattern.MatchString(fieldName)
+}
+
+// ToSnakeCase converts the camelCase field-name to snake_case, e.g. createdAt => created_at
+func ToSnakeCase(fieldName string) string {
+	var result strings.Builder
+	for i, char := range fieldName {
+		if unicode.IsUpper(char) {
+			if i > 0 {
+				result.WriteRune('_')
+			}
+			result.WriteRune(unicode.ToLower(char))
+		} else {
+			result.WriteRune(char)
+		}
+	}
+	return result.String()
+}
+
+// ToCamelCase converts the snake_case (or camelCase) name to camelCase, or PascalCase for upperFirst,
+// e.g. created_at => createdAt | CreatedAt
+func ToCamelCase(name string, upperFirst bool) string {
+	var result strings.Builder
+	upperNext := upperFirst
+	for _, char := range name {
+		if char == '_' {
+			upperNext = result.Len() > 0 || upperFirst
+			continue
+		}
+		if upperNext {
+			result.WriteRune(unicode.ToUpper(char))
+		} else if result.Len() == 0 {
+			result.WriteRune(unicode.ToLower(char))
+		} else {
+			result.WriteRune(char)
+		}
+		upperNext = false
+	}
+	return result.String()
+}
+
+// IsModelField validates the field-name against the model record-description (camelCase or snake_case),
+// including the base-model fields. Only the field-name format is validated, for empty record-description
+func IsModelField(fieldName string, recordDesc types.RecordDescType) bool {
+	if !IsFieldName(fieldName) {
+		return false
+	}
+	if len(recordDesc) < 1 {
+		return true
+	}
+	for _, desc := range []types.RecordDescType{recordDesc, types.BaseModel} {
+		for key := range desc {
+			if key == fieldName || ToSnakeCase(key) == fieldName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ComputeRowValue converts the raw row-value (e.g. from rows.Values()) to a json-compatible value, i.e. numeric
+// to float64 and uuid to string values
+func ComputeRowValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case pgtype.Numeric:
+		var fVal float64
+		if err := v.AssignTo(&fVal); err == nil {
+			return fVal
+		}
+	case [16]uint8:
+		return fmt.Sprintf("%x-%x-%x-%x-%x", v[0:4], v[4:6], v[6:8], v[8:10], v[10:16])
+	}
+	return val
+}
+
+// QuoteTableName quotes the table-name (or schema.table-name) as SQL identifier(s)
+func QuoteTableName(tableName string) string {
+	return pgx.Identifier(strings.Split(tableName, ".")).Sanitize()
+}
+
+// EscapeLikeValue escapes the LIKE-pattern special characters (\, % and _) in the value
+func EscapeLikeValue(val string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(val)
+}
+
+// ArraySQLInStringValues composes the quoted in-values script, e.g. 'a', 'b'
+// Deprecated: bind the values as a single array placeholder-value (field = ANY($n)), see ComputeIdsParam
 func ArraySQLInStringValues(arr []string) string {
 	result := ""
 	for ind, val := range arr {
-		result += "'" + val + "'"
+		result += "'" + strings.ReplaceAll(val, "'", "''") + "'"
 		if ind < len(arr)-1 {
 			result += ", "
 		}
@@ -78,6 +174,22 @@ func ArraySQLInStringValues(arr []string) string {
 	return result
 }
 
+// ComputeIdsParam computes the array placeholder-value for the record-ids (id = ANY($n)), as []string: the DB casts
+// the values to the (id) column type, e.g. bigint, uuid or text, i.e. the numeric-looking text-ids are not integers
+func ComputeIdsParam(recordIds []string) (interface{}, error) {
+	if len(recordIds) < 1 {
+		return nil, errors.New("record-ids are required")
+	}
+	var ids []string
+	for _, id := range recordIds {
+		if id == "" {
+			return nil, errors.New("record-ids must not include an empty id")
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 // JsonDataETL method converts json inputs to equivalent struct data type specification
 // rec must be a pointer to a type matching the jsonRec
 func JsonDataETL(jsonRec []byte, rec interface{}) error {
@@ -135,7 +247,7 @@ func StructToMap(rec interface{}) (map[string]interface{}, error) {
 	return mapData, nil
 }
 
-// TagField return the field-tag (e.g. table-column-name) for mcorm tag
+// TagField return the field-tag (e.g. table-column-name) for mcorm tag, without the tag-options
 func TagField(rec interface{}, fieldName string, tag string) (string, error) {
 	// TODO: validate rec as struct{}
 	t := reflect.TypeOf(rec)
@@ -149,7 +261,7 @@ func TagField(rec interface{}, fieldName string, tag string) (string, error) {
 		}
 	}
 	//tagValue := field.Tag
-	return field.Tag.Get(tag), nil
+	return strings.Split(field.Tag.Get(tag), ",")[0], nil
 }
 
 // StructToTagMap function converts struct to map (for crud-actionParams / records)
@@ -170,22 +282,82 @@ func StructToTagMap(rec interface{}, tag string) (map[string]interface{}, error)
 	return tagMapData, nil
 }
 
-// StructToFieldValues function converts struct/map to map (for DB columns and values)
+// IsStructType determines if the rec is a struct (or a pointer to a struct) value
+func IsStructType(rec interface{}) bool {
+	return reflect.Indirect(reflect.ValueOf(rec)).Kind() == reflect.Struct
+}
+
+// StructToFieldValues function converts struct to the tag-fields (for DB columns) and values, in the struct-fields
+// order. Struct-fields without the tag (or with tag "-") are skipped
 func StructToFieldValues(rec interface{}, tag string) ([]string, []interface{}, error) {
 	var tableFields []string
 	var fieldValues []interface{}
-	mapDataValue, err := StructToMap(rec)
-	if err != nil {
-		return nil, nil, errors.New("error computing struct to map")
+	v := reflect.Indirect(reflect.ValueOf(rec))
+	if v.Kind() != reflect.Struct {
+		return nil, nil, errors.New("invalid type - requires parameter of type struct only")
 	}
-	// compose tagMapDataValue
-	for key, val := range mapDataValue {
-		tagField, tagErr := TagField(rec.(struct{}), key, tag)
-		if tagErr != nil {
-			return nil, nil, errors.New(fmt.Sprintf("error retrieving tag-field: %v", key))
+	typeOfS := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		tagField := strings.Split(typeOfS.Field(i).Tag.Get(tag), ",")[0]
+		if tagField == "" || tagField == "-" {
+			continue
 		}
 		tableFields = append(tableFields, tagField)
-		fieldValues = append(fieldValues, val)
+		fieldValues = append(fieldValues, v.Field(i).Interface())
+	}
+	if len(tableFields) < 1 {
+		return nil, nil, errors.New(fmt.Sprintf("no %v tag-fields found for the struct", tag))
 	}
 	return tableFields, fieldValues, nil
 }
+
+// TagFieldName returns the toTag-name (e.g. json) of the struct-field with the specified tag-field
+// (e.g. mcorm column-name)
+func TagFieldName(rec interface{}, tag string, tagField string, toTag string) (string, error) {
+	recType := reflect.TypeOf(rec)
+	if recType != nil && recType.Kind() == reflect.Ptr {
+		recType = recType.Elem()
+	}
+	if recType == nil || recType.Kind() != reflect.Struct {
+		return "", errors.New("invalid type - requires parameter of type struct only")
+	}
+	for i := 0; i < recType.NumField(); i++ {
+		field := recType.Field(i)
+		if strings.Split(field.Tag.Get(tag), ",")[0] == tagField {
+			if toTagField := strings.Split(field.Tag.Get(toTag), ",")[0]; toTagField != "" && toTagField != "-" {
+				return toTagField, nil
+			}
+			return field.Name, nil
+		}
+	}
+	return "", errors.New(fmt.Sprintf("struct-field not found for the %v tag-field: %v", tag, tagField))
+}
+
+// StructScanFields function returns a pointer to a new (zero-value) struct of the rec type, and the pointers
+// to its struct-fields for the specified tag-fields, in order, as scan destinations
+func StructScanFields(rec interface{}, tag string, fields []string) (interface{}, []interface{}, error) {
+	recType := reflect.TypeOf(rec)
+	if recType != nil && recType.Kind() == reflect.Ptr {
+		recType = recType.Elem()
+	}
+	if recType == nil || recType.Kind() != reflect.Struct {
+		return nil, nil, errors.New("invalid type - requires parameter of type struct only")
+	}
+	recValue := reflect.New(recType)
+	fieldIndex := map[string]int{}
+	for i := 0; i < recType.NumField(); i++ {
+		tagField := strings.Split(recType.Field(i).Tag.Get(tag), ",")[0]
+		if tagField != "" && tagField != "-" {
+			fieldIndex[tagField] = i
+		}
+	}
+	var fieldPointers []interface{}
+	for _, field := range fields {
+		index, ok := fieldIndex[field]
+		if !ok {
+			return nil, nil, errors.New(fmt.Sprintf("struct-field not found for the %v tag-field: %v", tag, field))
+		}
+		fieldPointers = append(fieldPointers, recValue.Elem().Field(index).Addr().Interface())
+	}
+	return recValue.Interface(), fieldPointers, nil
+}
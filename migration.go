@@ -0,0 +1,207 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-16 | @Updated: 2021-01-16
+// @Company: mConnect.biz | @License: MIT
+// @Description: versioned migrations, tracked in the migration-history table
+
+package mcorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/helper"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Migrator object / struct
+type Migrator struct {
+	AppDb           *pgxpool.Pool
+	TableName       string // migration-history table | default: mcorm_migrations
+	Migrations      []types.MigrationType
+	AllowOutOfOrder bool // apply the pending migrations lower than the last applied version | default: false
+}
+
+// NewMigrator constructor returns a new migrator-instance, for the registered migrations
+func NewMigrator(appDb *pgxpool.Pool, migrations ...types.MigrationType) (migrator *Migrator) {
+	migrator = &Migrator{}
+	migrator.AppDb = appDb
+	migrator.TableName = helper.MigrationTable
+	migrator.Migrations = migrations
+	return migrator
+}
+
+// Register method registers the migration(s), to be applied in version order
+func (migrator *Migrator) Register(migrations ...types.MigrationType) {
+	migrator.Migrations = append(migrator.Migrations, migrations...)
+}
+
+// withConn method runs the migration task on a dedicated connection
+func (migrator *Migrator) withConn(task func(conn *pgxpool.Conn) error) error {
+	if migrator.AppDb == nil {
+		return errors.New("app-db is required to perform the migration tasks")
+	}
+	conn, err := migrator.AppDb.Acquire(context.Background())
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error acquiring db-connection: %v", err.Error()))
+	}
+	defer conn.Release()
+	return task(conn)
+}
+
+// withLock method runs the migration task on a dedicated connection, guarded by the advisory-lock of the
+// migration-history table, so that concurrent instances apply the migrations once, in order
+func (migrator *Migrator) withLock(task func(conn *pgxpool.Conn) error) error {
+	return migrator.withConn(func(conn *pgxpool.Conn) error {
+		lockKey := helper.ComputeMigrationLockKey(migrator.TableName)
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+			return errors.New(fmt.Sprintf("Error acquiring migration-lock: %v", err.Error()))
+		}
+		defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+		if _, err := conn.Exec(context.Background(), helper.ComputeMigrationTableQuery(migrator.TableName)); err != nil {
+			return errors.New(fmt.Sprintf("Error creating migration-history table: %v", err.Error()))
+		}
+		return task(conn)
+	})
+}
+
+// history method returns the applied migrations, ordered by version
+func (migrator *Migrator) history(conn *pgxpool.Conn) ([]types.MigrationHistoryType, error) {
+	historyQuery := fmt.Sprintf("SELECT version, name, checksum, applied_at FROM %v ORDER BY version", helper.QuoteTableName(migrator.TableName))
+	rows, err := conn.Query(context.Background(), historyQuery)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Db query Error [migration-history]: %v", err.Error()))
+	}
+	defer rows.Close()
+	var history []types.MigrationHistoryType
+	for rows.Next() {
+		var item types.MigrationHistoryType
+		if err = rows.Scan(&item.Version, &item.Name, &item.Checksum, &item.AppliedAt); err != nil {
+			return nil, errors.New(fmt.Sprintf("Error reading migration-history: %v", err.Error()))
+		}
+		history = append(history, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.New(fmt.Sprintf("Error reading migration-history: %v", err.Error()))
+	}
+	return history, nil
+}
+
+// migrate method applies (up) or rolls-back (down) the migration and updates the migration-history, in a transaction
+func (migrator *Migrator) migrate(conn *pgxpool.Conn, migration types.MigrationType, up bool) error {
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error starting the migration transaction: %v", err.Error()))
+	}
+	defer tx.Rollback(context.Background())
+	migrationSql, migrationFunc := migration.UpSql, migration.Up
+	historyQuery := fmt.Sprintf("INSERT INTO %v (version, name, checksum) VALUES ($1, $2, $3)", helper.QuoteTableName(migrator.TableName))
+	historyValues := []interface{}{migration.Version, migration.Name, helper.ComputeMigrationChecksum(migration)}
+	if !up {
+		migrationSql, migrationFunc = migration.DownSql, migration.Down
+		historyQuery = fmt.Sprintf("DELETE FROM %v WHERE version = $1", helper.QuoteTableName(migrator.TableName))
+		historyValues = []interface{}{migration.Version}
+	}
+	if migrationSql != "" {
+		if _, err = tx.Exec(context.Background(), migrationSql); err != nil {
+			return errors.New(fmt.Sprintf("migration [%v - %v]: %v", migration.Version, migration.Name, err.Error()))
+		}
+	}
+	if migrationFunc != nil {
+		if err = migrationFunc(tx); err != nil {
+			return errors.New(fmt.Sprintf("migration [%v - %v]: %v", migration.Version, migration.Name, err.Error()))
+		}
+	}
+	if _, err = tx.Exec(context.Background(), historyQuery, historyValues...); err != nil {
+		return errors.New(fmt.Sprintf("Error updating migration-history [%v]: %v", migration.Version, err.Error()))
+	}
+	if err = tx.Commit(context.Background()); err != nil {
+		return errors.New(fmt.Sprintf("Error committing the migration transaction [%v]: %v", migration.Version, err.Error()))
+	}
+	return nil
+}
+
+// Up method applies the pending migrations, in version order, each in a transaction, and returns the applied
+// migrations. The changed (checksum mismatch) applied migrations, and the out-of-order pending migrations (lower
+// than the last applied version, unless AllowOutOfOrder) are rejected, before applying any migration
+func (migrator *Migrator) Up() ([]types.MigrationType, error) {
+	var appliedMigrations []types.MigrationType
+	err := migrator.withLock(func(conn *pgxpool.Conn) error {
+		history, err := migrator.history(conn)
+		if err != nil {
+			return err
+		}
+		pendingMigrations, err := helper.ComputePendingMigrations(migrator.Migrations, history)
+		if err != nil {
+			return err
+		}
+		if outOfOrderMigrations := helper.ComputeOutOfOrderMigrations(pendingMigrations, history); len(outOfOrderMigrations) > 0 && !migrator.AllowOutOfOrder {
+			return errors.New(fmt.Sprintf("migration [%v - %v]: pending migration is lower than the last applied version, AllowOutOfOrder is required to apply it", outOfOrderMigrations[0].Version, outOfOrderMigrations[0].Name))
+		}
+		for _, migration := range pendingMigrations {
+			if err = migrator.migrate(conn, migration, true); err != nil {
+				return err
+			}
+			appliedMigrations = append(appliedMigrations, migration)
+		}
+		return nil
+	})
+	return appliedMigrations, err
+}
+
+// Down method rolls-back the last (steps) applied migrations, in reverse version order, each in a transaction,
+// and returns the rolled-back migrations | default steps: 1
+func (migrator *Migrator) Down(steps int) ([]types.MigrationType, error) {
+	if steps < 1 {
+		steps = 1
+	}
+	var revertedMigrations []types.MigrationType
+	err := migrator.withLock(func(conn *pgxpool.Conn) error {
+		history, err := migrator.history(conn)
+		if err != nil {
+			return err
+		}
+		migrations := map[int64]types.MigrationType{}
+		for _, migration := range migrator.Migrations {
+			migrations[migration.Version] = migration
+		}
+		for i := len(history) - 1; i >= 0 && len(revertedMigrations) < steps; i-- {
+			migration, ok := migrations[history[i].Version]
+			if !ok {
+				return errors.New(fmt.Sprintf("migration [%v - %v]: applied migration is not registered", history[i].Version, history[i].Name))
+			}
+			if migration.DownSql == "" && migration.Down == nil {
+				return errors.New(fmt.Sprintf("migration [%v - %v]: down-script or down-function is required", migration.Version, migration.Name))
+			}
+			if err = migrator.migrate(conn, migration, false); err != nil {
+				return err
+			}
+			revertedMigrations = append(revertedMigrations, migration)
+		}
+		return nil
+	})
+	return revertedMigrations, err
+}
+
+// Status method returns the applied migrations (history) and the pending migrations. Status is read-only: no
+// migration-lock or migration-history table (no history, if not exists)
+func (migrator *Migrator) Status() ([]types.MigrationHistoryType, []types.MigrationType, error) {
+	var (
+		history           []types.MigrationHistoryType
+		pendingMigrations []types.MigrationType
+	)
+	err := migrator.withConn(func(conn *pgxpool.Conn) error {
+		var tableExists bool
+		if err := conn.QueryRow(context.Background(), "SELECT to_regclass($1) IS NOT NULL", helper.QuoteTableName(migrator.TableName)).Scan(&tableExists); err != nil {
+			return errors.New(fmt.Sprintf("Db query Error [migration-history table]: %v", err.Error()))
+		}
+		var err error
+		if tableExists {
+			if history, err = migrator.history(conn); err != nil {
+				return err
+			}
+		}
+		pendingMigrations, err = helper.ComputePendingMigrations(migrator.Migrations, history)
+		return err
+	})
+	return history, pendingMigrations, err
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/abbeymart/mcresponse"
 	"github.com/asaskevich/govalidator"
 	"strconv"
+	"time"
 )
 
 type CrudOperations interface {
@@ -48,6 +49,11 @@ func NewModel(model types.ModelType) Model {
 	result.ComputedMethods = model.ComputedMethods
 	result.ValidateMethods = model.ValidateMethods
 	result.AlterSyncTable = model.AlterSyncTable
+	result.VersionStamp = model.VersionStamp
+	result.VersionField = model.VersionField
+	result.SoftDelete = model.SoftDelete
+	result.DropColumns = model.DropColumns
+	result.RecreateTable = model.RecreateTable
 
 	// Default values
 	if !result.TimeStamp {
@@ -63,6 +69,19 @@ func NewModel(model types.ModelType) Model {
 	return result
 }
 
+// NewModelFromStruct constructor: for table structure definition, with the record-description computed from the
+// mcorm tags of the struct record (see helper.ComputeTagFieldDesc), e.g. mcorm:"email,type=email,len=120,notnull,unique"
+// The model table-name defaults to the snake_case struct-name
+func NewModelFromStruct(rec interface{}, model types.ModelType) (Model, error) {
+	structModel, err := helper.StructToModel(rec, model.TableName)
+	if err != nil {
+		return Model{}, err
+	}
+	model.TableName = structModel.TableName
+	model.RecordDesc = structModel.RecordDesc
+	return NewModel(model), nil
+}
+
 // GetParentRelations method computes the parent-relations for the current model table
 func (model Model) GetParentRelations() []types.ModelRelationType {
 	// extract relations/collections where targetTable == model-TableName
@@ -292,6 +311,8 @@ func (model Model) ValidateRecordValue(modelRecordValue types.ActionParamType, T
 	validateErrorMessage := map[string]string{}
 	// perform model-recordValue validation
 	for key, recordFieldValue := range modelRecordValue {
+		// update-operator values (e.g. {"$inc": -1}) are computed by the DB, at update, i.e. no value-type check
+		_, _, isOperator := helper.ComputeUpdateOperator(recordFieldValue)
 		// check field description / definition exists
 		if recordFieldDesc, ok := recordDesc[key]; ok {
 			// transform recordFieldDesc to interface{} for type checking
@@ -301,7 +322,7 @@ func (model Model) ValidateRecordValue(modelRecordValue types.ActionParamType, T
 				// validate fieldValue and fieldDesc (model) types
 				// exception for fieldTypes: Text...
 				typePermitted := recordValueTypes[key] == datatypes.String && recordFieldDesc.FieldType == datatypes.Text
-				if recordValueTypes[key] != recordFieldDesc.FieldType && !typePermitted {
+				if recordValueTypes[key] != recordFieldDesc.FieldType && !typePermitted && !isOperator {
 					errMsg := fmt.Sprintf("Invalid Type for:  %v. Expected %v, Got %v", key, recordFieldDesc.FieldType, recordValueTypes[key])
 					if recordFieldDesc.ValidateMessage != "" {
 						validateErrorMessage[key] = recordFieldDesc.ValidateMessage + " :: " + errMsg
@@ -505,11 +526,37 @@ func (model Model) ValidateRecordValue(modelRecordValue types.ActionParamType, T
 	return types.ValidateResponseType{Ok: true, Errors: errMsg}
 }
 
+// ComputeCrudOptions method computes the crud-options defaults of the model: the optimistic-concurrency
+// version-field, the soft-delete field and the child-relations, for the referential-integrity (OnDelete) actions
+func (model Model) ComputeCrudOptions(options types.CrudOptionsType) types.CrudOptionsType {
+	if options.VersionField == "" {
+		options.VersionField = helper.ComputeVersionField(model.ModelType)
+	}
+	if options.SoftDeleteField == "" {
+		options.SoftDeleteField = helper.ComputeSoftDeleteField(model.ModelType)
+	}
+	if len(options.ChildRelations) == 0 {
+		options.ChildRelations = model.GetChildRelations()
+	}
+	return options
+}
+
 // Save method: sql.DB CRUD methods [pg, sqlite3...]
 // Save method performs create (new records) or update (for current/existing records) task
 func (model Model) Save(records []interface{}, params types.CrudParamsType, options types.CrudOptionsType) mcresponse.ResponseMessage {
 	// model specific params
 	params.TableName = model.TableName
+	options.RecordDesc = model.RecordDesc
+	// model primary/unique fields, for the default upsert conflict-target
+	if modelFields, err := helper.ComputeModelFields(model.ModelType); err == nil {
+		if len(options.PrimaryFields) == 0 {
+			options.PrimaryFields = helper.ComputePrimaryFields(modelFields)
+		}
+		if len(options.UniqueFields) == 0 {
+			options.UniqueFields = helper.ComputeUniqueFields(modelFields)
+		}
+	}
+	options = model.ComputeCrudOptions(options)
 	model.TaskType = params.TaskType
 	if model.TaskType == "" {
 		return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
@@ -555,15 +602,50 @@ func (model Model) Save(records []interface{}, params types.CrudParamsType, opti
 }
 
 // Get method query the DB by record-id, defined query-parameter or all records, constrained
-// by skip, limit and projected-field-parameters
-func (model Model) Get(rec interface{}, params types.CrudParamsType, options types.CrudOptionsType) mcresponse.ResponseMessage {
+// by skip, limit and projected-field-parameters. The related records of the include (relation names)
+// child-relations are eager-loaded and nested into each record
+func (model Model) Get(rec interface{}, params types.CrudParamsType, options types.CrudOptionsType, include ...string) mcresponse.ResponseMessage {
 	// model specific params
 	params.TableName = model.TableName
+	options.RecordDesc = model.RecordDesc
+	options = model.ComputeCrudOptions(options)
 
 	// instantiate Crud action
 	crud := NewCrud(params, options)
-	// TODO: perform get-task by RecordIds or QueryParams
- 	return crud.GetById(rec)
+	// perform get-task by RecordIds, QueryParams or all records
+	var getRes mcresponse.ResponseMessage
+	if len(params.RecordIds) > 0 {
+		getRes = crud.GetById(rec)
+	} else if len(params.QueryParams) > 0 {
+		getRes = crud.GetByParam(rec)
+	} else {
+		getRes = crud.GetAll(rec)
+	}
+	if getRes.Code != "success" || len(include) < 1 {
+		return getRes
+	}
+	// eager-load the included relations
+	getResult, ok := getRes.Value.(types.CrudResultType)
+	if !ok {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: "Error parsing the get-records result",
+			Value:   nil,
+		})
+	}
+	appDb := params.AppDb
+	if appDb == nil {
+		appDb = model.AppDb
+	}
+	tableRecords, err := model.IncludeRelations(appDb, rec, getResult.TableRecords, include)
+	if err != nil {
+		return mcresponse.GetResMessage("readError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error including the related records: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	getResult.TableRecords = tableRecords
+	getRes.Value = getResult
+	return getRes
 }
 
 // GetStream method query the DB by record-ids, defined query-parameter or all records, constrained
@@ -571,6 +653,8 @@ func (model Model) Get(rec interface{}, params types.CrudParamsType, options typ
 func (model Model) GetStream(rec interface{}, params types.CrudParamsType, options types.CrudOptionsType) mcresponse.ResponseMessage {
 	// model specific params
 	params.TableName = model.TableName
+	options.RecordDesc = model.RecordDesc
+	options = model.ComputeCrudOptions(options)
 
 	// instantiate Crud action
 	crud := NewCrud(params, options)
@@ -582,6 +666,8 @@ func (model Model) GetStream(rec interface{}, params types.CrudParamsType, optio
 func (model Model) DeleteById(params types.CrudParamsType, options types.CrudOptionsType) mcresponse.ResponseMessage {
 	// model specific params
 	params.TableName = model.TableName
+	options.RecordDesc = model.RecordDesc
+	options = model.ComputeCrudOptions(options)
 
 	// instantiate Crud action
 	crud := NewCrud(params, options)
@@ -593,6 +679,8 @@ func (model Model) DeleteById(params types.CrudParamsType, options types.CrudOpt
 func (model Model) DeleteByParam(params types.CrudParamsType, options types.CrudOptionsType) mcresponse.ResponseMessage {
 	// model specific params
 	params.TableName = model.TableName
+	options.RecordDesc = model.RecordDesc
+	options = model.ComputeCrudOptions(options)
 
 	// instantiate Crud action
 	crud := NewCrud(params, options)
@@ -604,9 +692,72 @@ func (model Model) DeleteByParam(params types.CrudParamsType, options types.Crud
 func (model Model) DeleteAll(params types.CrudParamsType, options types.CrudOptionsType) mcresponse.ResponseMessage {
 	// model specific params
 	params.TableName = model.TableName
+	options.RecordDesc = model.RecordDesc
+	options = model.ComputeCrudOptions(options)
 
 	// instantiate Crud action
 	crud := NewCrud(params, options)
 	// perform delete-task
 	return crud.DeleteAll()
 }
+
+// RestoreById method restore the soft-deleted record(s) by record-ids
+func (model Model) RestoreById(params types.CrudParamsType, options types.CrudOptionsType) mcresponse.ResponseMessage {
+	// model specific params
+	params.TableName = model.TableName
+	options.RecordDesc = model.RecordDesc
+	options = model.ComputeCrudOptions(options)
+
+	// instantiate Crud action
+	crud := NewCrud(params, options)
+	return crud.RestoreById()
+}
+
+// RestoreByParam method restore the soft-deleted record(s) by specified query-parameter
+func (model Model) RestoreByParam(params types.CrudParamsType, options types.CrudOptionsType) mcresponse.ResponseMessage {
+	// model specific params
+	params.TableName = model.TableName
+	options.RecordDesc = model.RecordDesc
+	options = model.ComputeCrudOptions(options)
+
+	// instantiate Crud action
+	crud := NewCrud(params, options)
+	return crud.RestoreByParam()
+}
+
+// PurgeById method permanently delete the soft-deleted record(s) by record-ids
+func (model Model) PurgeById(params types.CrudParamsType, options types.CrudOptionsType) mcresponse.ResponseMessage {
+	// model specific params
+	params.TableName = model.TableName
+	options.RecordDesc = model.RecordDesc
+	options = model.ComputeCrudOptions(options)
+
+	// instantiate Crud action
+	crud := NewCrud(params, options)
+	return crud.PurgeById()
+}
+
+// PurgeByParam method permanently delete the soft-deleted record(s) by specified query-parameter
+func (model Model) PurgeByParam(params types.CrudParamsType, options types.CrudOptionsType) mcresponse.ResponseMessage {
+	// model specific params
+	params.TableName = model.TableName
+	options.RecordDesc = model.RecordDesc
+	options = model.ComputeCrudOptions(options)
+
+	// instantiate Crud action
+	crud := NewCrud(params, options)
+	return crud.PurgeByParam()
+}
+
+// PurgeDeleted method permanently delete the record(s) soft-deleted before the deletedBefore time, e.g. after the
+// retention period
+func (model Model) PurgeDeleted(deletedBefore time.Time, params types.CrudParamsType, options types.CrudOptionsType) mcresponse.ResponseMessage {
+	// model specific params
+	params.TableName = model.TableName
+	options.RecordDesc = model.RecordDesc
+	options = model.ComputeCrudOptions(options)
+
+	// instantiate Crud action
+	crud := NewCrud(params, options)
+	return crud.PurgeDeleted(deletedBefore)
+}
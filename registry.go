@@ -0,0 +1,59 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-17 | @Updated: 2021-01-17
+// @Company: mConnect.biz | @License: MIT
+// @Description: registry of the models, migrations and seeds, for the mcorm command-line tool
+
+package mcorm
+
+import (
+	"github.com/abbeymart/mcorm/types"
+	"sync"
+)
+
+var (
+	registryMutex        sync.Mutex
+	registeredModels     []types.ModelType
+	registeredMigrations []types.MigrationType
+	registeredSeeds      []types.SeedType
+)
+
+// RegisterModels registers the model(s), e.g. from the init function of the models package
+func RegisterModels(models ...types.ModelType) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registeredModels = append(registeredModels, models...)
+}
+
+// RegisteredModels returns the registered models, in registration order
+func RegisteredModels() []types.ModelType {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	return append([]types.ModelType{}, registeredModels...)
+}
+
+// RegisterMigrations registers the versioned migration(s)
+func RegisterMigrations(migrations ...types.MigrationType) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registeredMigrations = append(registeredMigrations, migrations...)
+}
+
+// RegisteredMigrations returns the registered migrations, in registration order
+func RegisteredMigrations() []types.MigrationType {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	return append([]types.MigrationType{}, registeredMigrations...)
+}
+
+// RegisterSeeds registers the seed(s), to be run in registration order
+func RegisterSeeds(seeds ...types.SeedType) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registeredSeeds = append(registeredSeeds, seeds...)
+}
+
+// RegisteredSeeds returns the registered seeds, in registration order
+func RegisteredSeeds() []types.SeedType {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	return append([]types.SeedType{}, registeredSeeds...)
+}
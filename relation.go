@@ -0,0 +1,127 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-14 | @Updated: 2021-01-14
+// @Company: mConnect.biz | @License: MIT
+// @Description: eager-loading of related records, by model-relations
+
+package mcorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/helper"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcorm/types/ormRelations"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"strings"
+)
+
+// IncludeRelations method eager-loads the related records of the specified (include) child-relation names, with one
+// batched query per relation, and nests them into a copy of each record (map), by relation name:
+// a list of records for one-to-many and many-to-many relations, a record (or nil) for one-to-one and many-to-one.
+// The rec (struct) maps the relation source-field (mcorm tag) to the records' field-name (json tag)
+func (model Model) IncludeRelations(appDb *pgxpool.Pool, rec interface{}, records []interface{}, include []string) ([]interface{}, error) {
+	// included relations, by name
+	relations := map[string]types.ModelRelationType{}
+	for _, relation := range model.GetChildRelations() {
+		relations[helper.ComputeRelationName(relation)] = relation
+	}
+	var includeRelations []types.ModelRelationType
+	for _, relationName := range include {
+		relation, ok := relations[relationName]
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("Unknown relation [%v] for the table: %v", relationName, model.TableName))
+		}
+		includeRelations = append(includeRelations, relation)
+	}
+	// copy the records, to nest the related records
+	var tableRecords []map[string]interface{}
+	for _, record := range records {
+		recordMap, ok := record.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("records of type map[string]interface{} are required to include the related records")
+		}
+		tableRecord := map[string]interface{}{}
+		for key, val := range recordMap {
+			tableRecord[key] = val
+		}
+		tableRecords = append(tableRecords, tableRecord)
+	}
+	for _, relation := range includeRelations {
+		relationName := helper.ComputeRelationName(relation)
+		sourceField, err := helper.TagFieldName(rec, "mcorm", relation.SourceField, "json")
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("source-field [%v] is required in the record for the relation [%v]: %v", relation.SourceField, relationName, err.Error()))
+		}
+		// distinct relation-keys (source-field-values) of the records
+		var relationKeys []string
+		for _, tableRecord := range tableRecords {
+			relationKey := helper.ComputeRelationKey(tableRecord[sourceField])
+			if relationKey != "" && !helper.ArrayStringContains(relationKeys, relationKey) {
+				relationKeys = append(relationKeys, relationKey)
+			}
+		}
+		relatedRecords := map[string][]interface{}{}
+		if len(relationKeys) > 0 {
+			relatedRecords, err = model.GetRelatedRecords(appDb, relation, relationKeys)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// nest the related records, by relation-type
+		for _, tableRecord := range tableRecords {
+			related := relatedRecords[helper.ComputeRelationKey(tableRecord[sourceField])]
+			switch strings.ToLower(relation.RelationType) {
+			case ormRelations.OneToMany, ormRelations.ManyToMany:
+				if related == nil {
+					related = []interface{}{}
+				}
+				tableRecord[relationName] = related
+			default:
+				if len(related) > 0 {
+					tableRecord[relationName] = related[0]
+				} else {
+					tableRecord[relationName] = nil
+				}
+			}
+		}
+	}
+	var results []interface{}
+	for _, tableRecord := range tableRecords {
+		results = append(results, tableRecord)
+	}
+	return results, nil
+}
+
+// GetRelatedRecords method fetches the related (target) records of the relation-keys (source-field-values), in
+// a single query, and returns the related records (maps, by table-field), grouped by relation-key
+func (model Model) GetRelatedRecords(appDb *pgxpool.Pool, relation types.ModelRelationType, relationKeys []string) (map[string][]interface{}, error) {
+	relationQuery, err := helper.ComputeRelationQuery(relation, relationKeys)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := appDb.Query(context.Background(), relationQuery.SelectQuery, relationQuery.FieldValues...)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Db query Error [relation: %v]: %v", helper.ComputeRelationName(relation), err.Error()))
+	}
+	defer rows.Close()
+	relatedRecords := map[string][]interface{}{}
+	for rows.Next() {
+		rowValues, rowErr := rows.Values()
+		if rowErr != nil {
+			return nil, errors.New(fmt.Sprintf("Error reading/getting related records[row-values]: %v", rowErr.Error()))
+		}
+		relatedRecord := map[string]interface{}{}
+		// the first field is the relation-key
+		for i, field := range rows.FieldDescriptions() {
+			if i > 0 {
+				relatedRecord[string(field.Name)] = helper.ComputeRowValue(rowValues[i])
+			}
+		}
+		relationKey := helper.ComputeRelationKey(helper.ComputeRowValue(rowValues[0]))
+		relatedRecords[relationKey] = append(relatedRecords[relationKey], relatedRecord)
+	}
+	if rowErr := rows.Err(); rowErr != nil {
+		return nil, errors.New(fmt.Sprintf("Error reading/getting related records: %v", rowErr.Error()))
+	}
+	return relatedRecords, nil
+}
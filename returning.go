@@ -0,0 +1,55 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-21 | @Updated: 2021-01-21
+// @Company: mConnect.biz | @License: MIT
+// @Description: return (RETURNING) the affected rows of the create, update and delete tasks
+
+package mcorm
+
+import (
+	"context"
+	"github.com/abbeymart/mcorm/helper"
+	"github.com/jackc/pgx/v4"
+	"reflect"
+)
+
+// queryFuncType is the Query method of the pgx connection-pool or transaction
+type queryFuncType func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+
+// ComputeReturningFields method computes the returning table-fields of the ReturnRecord type | nil, if the
+// affected rows are not returned
+func (crud *Crud) ComputeReturningFields() ([]string, error) {
+	if crud.ReturnRecord == nil {
+		return nil, nil
+	}
+	return helper.ComputeReturningFields(crud.ReturnRecord)
+}
+
+// scanReturningRecord method scans the returned row into a new ReturnRecord, after the lead-columns
+// (e.g. RETURNING id), and returns the record-value, of the ReturnRecord (struct) type
+func (crud *Crud) scanReturningRecord(scan func(dest ...interface{}) error, returningFields []string, leadPointers ...interface{}) (interface{}, error) {
+	returnRec, fieldPointers, err := helper.StructScanFields(crud.ReturnRecord, "mcorm", returningFields)
+	if err != nil {
+		return nil, err
+	}
+	if err = scan(append(leadPointers, fieldPointers...)...); err != nil {
+		return nil, err
+	}
+	return reflect.Indirect(reflect.ValueOf(returnRec)).Interface(), nil
+}
+
+// queryReturningRecords method performs the RETURNING (returning-fields only) query, and returns the affected rows
+func (crud *Crud) queryReturningRecords(query queryFuncType, sql string, fieldValues []interface{}, returningFields []string) ([]interface{}, error) {
+	rows, err := query(context.Background(), sql, fieldValues...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var returnRecs []interface{}
+	for rows.Next() {
+		returnRec, scanErr := crud.scanReturningRecord(rows.Scan, returningFields)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		returnRecs = append(returnRecs, returnRec)
+	}
+	return returnRecs, rows.Err()
+}
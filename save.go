@@ -6,6 +6,7 @@ package mcorm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/abbeymart/mcauditlog"
 	"github.com/abbeymart/mccache"
@@ -14,6 +15,7 @@ import (
 	"github.com/abbeymart/mcorm/types/tasks"
 	"github.com/abbeymart/mcresponse"
 	"github.com/jackc/pgx/v4"
+	"strings"
 )
 
 // Save method creates new record(s) or updates existing record(s)
@@ -53,6 +55,10 @@ func (crud *Crud) Save(records []interface{}) mcresponse.ResponseMessage {
 			Value:   nil,
 		})
 	}
+	// upsert (insert or update on conflict) all the records, in upsert mode
+	if crud.UpsertParams.Upsert {
+		return crud.CreateBatch(actionParams, tableFields)
+	}
 	// compute records for insert/create or update operation
 	for _, rec := range actionParams {
 		// determine if record existed (update) or is new (create)
@@ -89,6 +95,10 @@ func (crud *Crud) Save(records []interface{}) mcresponse.ResponseMessage {
 
 	if len(createRecs) > 0 {
 		// save-record(s): create/insert new record(s), recordIds = @[], if len(createRecs) > 0
+		// large batches (CopyThreshold) are created via COPY
+		if crud.CopyThreshold > 0 && len(createRecs) >= crud.CopyThreshold {
+			return crud.CreateCopy(createRecs, tableFields)
+		}
 		return crud.CreateBatch(createRecs, tableFields)
 	}
 
@@ -124,6 +134,14 @@ func (crud *Crud) Create(createRecs types.ActionParamsType, tableFields []string
 			Value:   nil,
 		})
 	}
+	// returning-fields, to return the created records
+	returningFields, rErr := crud.ComputeReturningFields()
+	if rErr != nil {
+		return mcresponse.GetResMessage("insertError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing returning-fields: %v", rErr.Error()),
+			Value:   nil,
+		})
+	}
 	// perform create/insert action, via transaction/copy-protocol:
 	tx, txErr := crud.AppDb.Begin(context.Background())
 	if txErr != nil {
@@ -138,8 +156,19 @@ func (crud *Crud) Create(createRecs types.ActionParamsType, tableFields []string
 	insertCount := 0
 	var insertIds []string
 	var insertId string
+	var returnRecs []interface{}
 	for _, insertQuery := range createQuery {
-		insertErr := tx.QueryRow(context.Background(), insertQuery).Scan(&insertId)
+		var insertErr error
+		if len(returningFields) > 0 {
+			var returnRec interface{}
+			insertQuery, insertErr = helper.ComputeReturningQuery(insertQuery, returningFields)
+			if insertErr == nil {
+				returnRec, insertErr = crud.scanReturningRecord(tx.QueryRow(context.Background(), insertQuery).Scan, returningFields, &insertId)
+				returnRecs = append(returnRecs, returnRec)
+			}
+		} else {
+			insertErr = tx.QueryRow(context.Background(), insertQuery).Scan(&insertId)
+		}
 		if insertErr != nil {
 			_ = tx.Rollback(context.Background())
 			return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
@@ -178,19 +207,40 @@ func (crud *Crud) Create(createRecs types.ActionParamsType, tableFields []string
 	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
 		Message: logMessage,
 		Value: types.CrudResultType{
-			RecordIds:   insertIds,
-			RecordCount: insertCount,
+			RecordIds:    insertIds,
+			RecordCount:  insertCount,
+			TableRecords: returnRecs,
 		},
 	})
 }
 
 // CreateBatch method creates new record(s) by placeholder values from copy-create-query
 // resolve sql-values parsing error: only time.Time and String value requires '' wrapping
-// uuid, json and others (int/bool/float) should not be wrapped as placeholder values
+// uuid, json and others (int/bool/float) should not be wrapped as placeholder values.
+// In upsert mode (UpsertParams), the conflicting records are updated or skipped (DO NOTHING), and the
+// per-record inserted/updated/skipped results are returned, as UpsertResults. With the ReturnRecord type, the
+// created (or updated) records are returned, as TableRecords
 func (crud *Crud) CreateBatch(createRecs types.ActionParamsType, tableFields []string) mcresponse.ResponseMessage {
 	// create from createRecs (actionParams)
 	// compute query
-	createQuery, qErr := helper.ComputeCreateCopyQuery(crud.TableName, createRecs, tableFields)
+	var createQuery types.CreateQueryResponseType
+	var qErr error
+	if crud.UpsertParams.Upsert {
+		createQuery, qErr = helper.ComputeUpsertQuery(crud.TableName, createRecs, tableFields, crud.UpsertParams, crud.PrimaryFields, crud.UniqueFields)
+	} else {
+		createQuery, qErr = helper.ComputeCreateCopyQuery(crud.TableName, createRecs, tableFields)
+	}
+	// returning-fields, to return the created records
+	returningFields, rErr := crud.ComputeReturningFields()
+	if qErr == nil && rErr == nil && len(returningFields) > 0 {
+		createQuery.CreateQuery, qErr = helper.ComputeReturningQuery(createQuery.CreateQuery, returningFields)
+	}
+	if rErr != nil {
+		return mcresponse.GetResMessage("insertError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing returning-fields: %v", rErr.Error()),
+			Value:   nil,
+		})
+	}
 	if qErr != nil {
 		return mcresponse.GetResMessage("insertError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Error computing create-query: %v", qErr.Error()),
@@ -207,24 +257,16 @@ func (crud *Crud) CreateBatch(createRecs types.ActionParamsType, tableFields []s
 	}
 	defer tx.Rollback(context.Background())
 
-	// perform records' creation
-	insertCount := 0
-	var insertIds []string
-	var insertId string
-	for _, iValues := range createQuery.FieldValues {
-		//fmt.Printf("query: %v\n\n", createQuery.CreateQuery)
-		//fmt.Printf("query-value: %v \n\n", iValues)
-		insertErr := tx.QueryRow(context.Background(), createQuery.CreateQuery, iValues...).Scan(&insertId)
-		if insertErr != nil {
-			_ = tx.Rollback(context.Background())
-			return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
-				Message: fmt.Sprintf("Error updating record(s): %v", insertErr.Error()),
-				Value:   nil,
-			})
-		}
-		insertCount += 1
-		insertIds = append(insertIds, insertId)
+	// perform records' creation, in one (batch) network round-trip
+	insertIds, upsertResults, returnRecs, insertErr := crud.sendCreateBatch(tx, createQuery, returningFields)
+	if insertErr != nil {
+		_ = tx.Rollback(context.Background())
+		return mcresponse.GetResMessage("insertError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error creating new record(s): %v", insertErr.Error()),
+			Value:   nil,
+		})
 	}
+	insertCount := len(insertIds)
 	// commit
 	txcErr := tx.Commit(context.Background())
 	if txcErr != nil {
@@ -253,27 +295,103 @@ func (crud *Crud) CreateBatch(createRecs types.ActionParamsType, tableFields []s
 	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
 		Message: logMessage,
 		Value: types.CrudResultType{
-			RecordIds:   insertIds,
-			RecordCount: insertCount,
+			RecordIds:     insertIds,
+			RecordCount:   insertCount,
+			TableRecords:  returnRecs,
+			UpsertResults: upsertResults,
 		},
 	})
 }
 
-// CreateCopy method creates new record(s) using Pg CopyFrom
-// TODO: resolve sql-values parsing error (incorrect binary data format (SQLSTATE 22P03) - ?uuid primary key?)
+// sendCreateBatch method queues the create-query of each record (placeholder-values) into a pgx.Batch, sent
+// in one network round-trip, and returns the insert-ids, in upsert mode, the per-record upsert-results and, with
+// the returning-fields, the created records. The failed record is reported by index (Record #n)
+func (crud *Crud) sendCreateBatch(tx pgx.Tx, createQuery types.CreateQueryResponseType, returningFields []string) ([]string, []types.UpsertResultType, []interface{}, error) {
+	batch := &pgx.Batch{}
+	for _, iValues := range createQuery.FieldValues {
+		batch.Queue(createQuery.CreateQuery, iValues...)
+	}
+	batchResults := tx.SendBatch(context.Background(), batch)
+	defer batchResults.Close()
+	var insertIds []string
+	var upsertResults []types.UpsertResultType
+	var returnRecs []interface{}
+	for recNum := range createQuery.FieldValues {
+		var insertId string
+		var inserted bool
+		leadPointers := []interface{}{&insertId}
+		if crud.UpsertParams.Upsert {
+			leadPointers = append(leadPointers, &inserted)
+		}
+		var returnRec interface{}
+		var insertErr error
+		row := batchResults.QueryRow()
+		if len(returningFields) > 0 {
+			returnRec, insertErr = crud.scanReturningRecord(row.Scan, returningFields, leadPointers...)
+		} else {
+			insertErr = row.Scan(leadPointers...)
+		}
+		if crud.UpsertParams.Upsert && insertErr == pgx.ErrNoRows {
+			// DO NOTHING: conflicting record skipped
+			upsertResults = append(upsertResults, types.UpsertResultType{Skipped: true})
+			continue
+		}
+		if insertErr != nil {
+			return nil, nil, nil, errors.New(fmt.Sprintf("Record #%v: %v", recNum, insertErr.Error()))
+		}
+		if crud.UpsertParams.Upsert {
+			upsertResults = append(upsertResults, types.UpsertResultType{RecordId: insertId, Inserted: inserted})
+		}
+		insertIds = append(insertIds, insertId)
+		if returnRec != nil {
+			returnRecs = append(returnRecs, returnRec)
+		}
+	}
+	if err := batchResults.Close(); err != nil {
+		return nil, nil, nil, err
+	}
+	return insertIds, upsertResults, returnRecs, nil
+}
+
+// CreateCopy method creates new record(s) using Pg CopyFrom, in chunks (CopyChunkSize), via the temp-table
+// of the table-fields: the copied records of each chunk are inserted into the table, returning the generated ids.
+// The record-values are converted to the pgtype encoders of the live table-columns (uuid, json/jsonb,
+// timestamptz, numeric, arrays...), and the progress is reported (CopyProgress) after each chunk
 func (crud *Crud) CreateCopy(createRecs types.ActionParamsType, tableFields []string) mcresponse.ResponseMessage {
 	// create from createRecs (actionParams)
-	// compute query
-	createQuery, qErr := helper.ComputeCreateCopyQuery(crud.TableName, createRecs, tableFields)
+	// compute copy-rows, by the table-columns types
+	tableColumns, err := helper.GetTableColumns(crud.TableName, crud.AppDb)
+	if err != nil {
+		return mcresponse.GetResMessage("insertError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing table-columns: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	copyRows, err := helper.ComputeCopyRows(createRecs, tableFields, tableColumns)
+	if err != nil {
+		return mcresponse.GetResMessage("insertError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing copy-values: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	copyQuery, qErr := helper.ComputeCopyQuery(crud.TableName, tableFields)
+	// returning-fields, to return the created records
+	returningFields, rErr := crud.ComputeReturningFields()
+	if rErr != nil {
+		return mcresponse.GetResMessage("insertError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing returning-fields: %v", rErr.Error()),
+			Value:   nil,
+		})
+	}
+	if qErr == nil && len(returningFields) > 0 {
+		copyQuery.InsertQuery, qErr = helper.ComputeReturningQuery(copyQuery.InsertQuery, returningFields)
+	}
 	if qErr != nil {
 		return mcresponse.GetResMessage("insertError", mcresponse.ResponseMessageOptions{
-			Message: fmt.Sprintf("Error computing create-query: %v", qErr.Error()),
+			Message: fmt.Sprintf("Error computing copy-query: %v", qErr.Error()),
 			Value:   nil,
 		})
 	}
-	//fmt.Printf("create-query: %v \n", createQuery)
-	//fmt.Printf("create-query-fields: %v \n", createQuery.FieldNames)
-	//fmt.Printf("create-query-values: %v \n\n", createQuery.FieldValues)
 	// perform create/insert action, via transaction/copy-protocol:
 	tx, txErr := crud.AppDb.Begin(context.Background())
 	if txErr != nil {
@@ -283,21 +401,46 @@ func (crud *Crud) CreateCopy(createRecs types.ActionParamsType, tableFields []st
 		})
 	}
 	defer tx.Rollback(context.Background())
-
-	// bulk create
-	copyCount, cErr := tx.CopyFrom(
-		context.Background(),
-		pgx.Identifier{crud.TableName},
-		createQuery.FieldNames,
-		pgx.CopyFromRows(createQuery.FieldValues),
-	)
-	if cErr != nil {
+	if _, err = tx.Exec(context.Background(), copyQuery.CreateQuery); err != nil {
 		_ = tx.Rollback(context.Background())
 		return mcresponse.GetResMessage("insertError", mcresponse.ResponseMessageOptions{
-			Message: fmt.Sprintf("Error creating new record(s): %v", cErr.Error()),
+			Message: fmt.Sprintf("Error creating copy-table: %v", err.Error()),
 			Value:   nil,
 		})
 	}
+
+	// bulk create, by chunks
+	copyCount := 0
+	var insertIds []string
+	var returnRecs []interface{}
+	for _, copyChunk := range helper.ComputeCopyChunks(copyRows, crud.CopyChunkSize) {
+		if _, cErr := tx.CopyFrom(
+			context.Background(),
+			pgx.Identifier{copyQuery.CopyTable},
+			tableFields,
+			pgx.CopyFromRows(copyChunk),
+		); cErr != nil {
+			_ = tx.Rollback(context.Background())
+			return mcresponse.GetResMessage("insertError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error creating new record(s): %v", cErr.Error()),
+				Value:   nil,
+			})
+		}
+		chunkIds, chunkRecs, iErr := crud.copyInsert(tx, copyQuery, returningFields)
+		if iErr != nil {
+			_ = tx.Rollback(context.Background())
+			return mcresponse.GetResMessage("insertError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error creating new record(s): %v", iErr.Error()),
+				Value:   nil,
+			})
+		}
+		insertIds = append(insertIds, chunkIds...)
+		returnRecs = append(returnRecs, chunkRecs...)
+		copyCount += len(chunkIds)
+		if crud.CopyProgress != nil {
+			crud.CopyProgress(copyCount, len(copyRows))
+		}
+	}
 	// commit
 	txcErr := tx.Commit(context.Background())
 	if txcErr != nil {
@@ -327,22 +470,76 @@ func (crud *Crud) CreateCopy(createRecs types.ActionParamsType, tableFields []st
 	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
 		Message: logMessage,
 		Value: types.CrudResultType{
-			RecordIds:   crud.RecordIds,
-			RecordCount: int(copyCount),
+			RecordIds:    insertIds,
+			RecordCount:  copyCount,
+			TableRecords: returnRecs,
 		},
 	})
 }
 
-// Update method updates existing record(s)
+// copyInsert method inserts the copied records (chunk) into the table, returning the generated ids and, with the
+// returning-fields, the created records, and clears the copy-table, for the next chunk
+func (crud *Crud) copyInsert(tx pgx.Tx, copyQuery types.CopyQueryResponseType, returningFields []string) ([]string, []interface{}, error) {
+	rows, err := tx.Query(context.Background(), copyQuery.InsertQuery)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var insertIds []string
+	var returnRecs []interface{}
+	for rows.Next() {
+		var insertId string
+		if len(returningFields) > 0 {
+			returnRec, scanErr := crud.scanReturningRecord(rows.Scan, returningFields, &insertId)
+			if scanErr != nil {
+				return nil, nil, scanErr
+			}
+			returnRecs = append(returnRecs, returnRec)
+		} else if err = rows.Scan(&insertId); err != nil {
+			return nil, nil, err
+		}
+		insertIds = append(insertIds, insertId)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	rows.Close()
+	if _, err = tx.Exec(context.Background(), copyQuery.ClearQuery); err != nil {
+		return nil, nil, err
+	}
+	return insertIds, returnRecs, nil
+}
+
+// Update method updates existing record(s). With the optimistic-concurrency VersionField, each record update
+// is checked by the record's version-value, and the stale records (changed by another update) are rejected,
+// with the conflictError response. With the ReturnRecord type, the updated records are returned, as TableRecords
 func (crud *Crud) Update(updateRecs types.ActionParamsType, tableFields []string) mcresponse.ResponseMessage {
 	// create from updatedRecs (actionParams)
-	updateQuery, err := helper.ComputeUpdateQuery(crud.TableName, updateRecs, tableFields)
+	var updateQuery types.UpdateQueryResponseType
+	var err error
+	if crud.VersionField != "" {
+		updateQuery, err = helper.ComputeVersionUpdateQuery(crud.TableName, updateRecs, tableFields, crud.VersionField)
+	} else {
+		updateQuery, err = helper.ComputeUpdateQuery(crud.TableName, updateRecs, tableFields)
+	}
+	updateQuery, err = crud.activeUpdate(updateQuery, err)
 	if err != nil {
 		return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Error computing update-query: %v", err.Error()),
 			Value:   nil,
 		})
 	}
+	// returning-fields, to return the updated records
+	returningFields, err := crud.ComputeReturningFields()
+	if err == nil && len(returningFields) > 0 {
+		updateQuery.UpdateQuery, err = helper.ComputeReturningQuery(updateQuery.UpdateQuery, returningFields)
+	}
+	if err != nil {
+		return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing returning-query: %v", err.Error()),
+			Value:   nil,
+		})
+	}
 	// perform update action, via transaction:
 	tx, txErr := crud.AppDb.Begin(context.Background())
 	if txErr != nil {
@@ -352,19 +549,31 @@ func (crud *Crud) Update(updateRecs types.ActionParamsType, tableFields []string
 		})
 	}
 	defer tx.Rollback(context.Background())
-	// perform records' updates
-	updateCount := 0
-	//fmt.Printf("update-queries: %v\n", updateQuery)
-	for _, upQuery := range updateQuery {
-		commandTag, updateErr := tx.Exec(context.Background(), upQuery)
-		if updateErr != nil {
-			_ = tx.Rollback(context.Background())
-			return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
-				Message: fmt.Sprintf("Error updating record(s): %v", updateErr.Error()),
-				Value:   nil,
-			})
+	// prepare the update-statement once, and perform records' updates, in one (batch) network round-trip
+	statementName := helper.ComputeStatementName(updateQuery.UpdateQuery)
+	if _, err = tx.Prepare(context.Background(), statementName, updateQuery.UpdateQuery); err != nil {
+		_ = tx.Rollback(context.Background())
+		return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error preparing update-query: %v", err.Error()),
+			Value:   nil,
+		})
+	}
+	updateCount, staleRecNums, returnRecs, updateErr := crud.sendUpdateBatch(tx, statementName, updateQuery.RowValues, returningFields)
+	if updateErr != nil {
+		_ = tx.Rollback(context.Background())
+		return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error updating record(s): %v", updateErr.Error()),
+			Value:   nil,
+		})
+	}
+	// version-check conflict: reject the update of all the records
+	if crud.VersionField != "" && len(staleRecNums) > 0 {
+		_ = tx.Rollback(context.Background())
+		var staleIds []string
+		for _, recNum := range staleRecNums {
+			staleIds = append(staleIds, fmt.Sprintf("%v", updateRecs[recNum]["id"]))
 		}
-		updateCount += int(commandTag.RowsAffected())
+		return crud.conflictMessage(staleIds)
 	}
 	// commit
 	txcErr := tx.Commit(context.Background())
@@ -382,23 +591,114 @@ func (crud *Crud) Update(updateRecs types.ActionParamsType, tableFields []string
 	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
 		Message: "Record(s) update completed successfully",
 		Value: types.CrudResultType{
-			QueryParam:  crud.QueryParams,
-			RecordIds:   crud.RecordIds,
-			RecordCount: updateCount,
+			QueryParam:   crud.QueryParams,
+			RecordIds:    crud.RecordIds,
+			RecordCount:  updateCount,
+			TableRecords: returnRecs,
+		},
+	})
+}
+
+// sendUpdateBatch method queues the (prepared) update-statement of each record-values into a pgx.Batch, sent
+// in one network round-trip, and returns the updated records count, the record-indexes of the unmatched
+// (zero rows updated) records and, with the returning-fields, the updated records. The failed record is
+// reported by index (Record #n)
+func (crud *Crud) sendUpdateBatch(tx pgx.Tx, statementName string, rowValues [][]interface{}, returningFields []string) (int, []int, []interface{}, error) {
+	batch := &pgx.Batch{}
+	for _, recValues := range rowValues {
+		batch.Queue(statementName, recValues...)
+	}
+	batchResults := tx.SendBatch(context.Background(), batch)
+	defer batchResults.Close()
+	updateCount := 0
+	var unmatchedRecNums []int
+	var returnRecs []interface{}
+	for recNum := range rowValues {
+		recCount := 0
+		if len(returningFields) > 0 {
+			recs, updateErr := crud.scanReturningRows(batchResults, returningFields)
+			if updateErr != nil {
+				return 0, nil, nil, errors.New(fmt.Sprintf("Record #%v: %v", recNum, updateErr.Error()))
+			}
+			recCount = len(recs)
+			returnRecs = append(returnRecs, recs...)
+		} else {
+			commandTag, updateErr := batchResults.Exec()
+			if updateErr != nil {
+				return 0, nil, nil, errors.New(fmt.Sprintf("Record #%v: %v", recNum, updateErr.Error()))
+			}
+			recCount = int(commandTag.RowsAffected())
+		}
+		if recCount == 0 {
+			unmatchedRecNums = append(unmatchedRecNums, recNum)
+		}
+		updateCount += recCount
+	}
+	if err := batchResults.Close(); err != nil {
+		return 0, nil, nil, err
+	}
+	return updateCount, unmatchedRecNums, returnRecs, nil
+}
+
+// scanReturningRows method scans the returned rows of the next (queued) batch-query into the ReturnRecords
+func (crud *Crud) scanReturningRows(batchResults pgx.BatchResults, returningFields []string) ([]interface{}, error) {
+	rows, err := batchResults.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var returnRecs []interface{}
+	for rows.Next() {
+		returnRec, scanErr := crud.scanReturningRecord(rows.Scan, returningFields)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		returnRecs = append(returnRecs, returnRec)
+	}
+	return returnRecs, rows.Err()
+}
+
+// conflictMessage method returns the optimistic-concurrency conflictError response, of the stale record-ids
+func (crud *Crud) conflictMessage(staleIds []string) mcresponse.ResponseMessage {
+	return mcresponse.GetResMessage("conflictError", mcresponse.ResponseMessageOptions{
+		Message: fmt.Sprintf("Stale record(s), changed by another update: %v", strings.Join(staleIds, ", ")),
+		Value: types.CrudResultType{
+			StaleRecordIds: staleIds,
 		},
 	})
 }
 
-// UpdateById method updates existing records (in batch) that met the specified record-id(s)
+// UpdateById method updates existing records (in batch) that met the specified record-id(s). With the
+// optimistic-concurrency VersionField, the records are checked by the record's (actionParams) version-value,
+// and the stale records are rejected, with the conflictError response. With the ReturnRecord type, the updated
+// records are returned, as TableRecords
 func (crud *Crud) UpdateById(updateRecs types.ActionParamsType, tableFields []string) mcresponse.ResponseMessage {
 	// create from updatedRecs (actionParams)
-	updateQuery, err := helper.ComputeUpdateQueryById(crud.TableName, updateRecs, crud.RecordIds, tableFields)
+	var updateQuery types.UpdateQueryResponseType
+	var err error
+	if crud.VersionField != "" {
+		updateQuery, err = helper.ComputeVersionUpdateQueryById(crud.TableName, updateRecs, crud.RecordIds, tableFields, crud.VersionField)
+	} else {
+		updateQuery, err = helper.ComputeUpdateQueryById(crud.TableName, updateRecs, crud.RecordIds, tableFields)
+	}
+	updateQuery, err = crud.activeUpdate(updateQuery, err)
 	if err != nil {
 		return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Error computing update-query: %v", err.Error()),
 			Value:   nil,
 		})
 	}
+	// returning-fields, to return the updated records
+	returningFields, err := crud.ComputeReturningFields()
+	if err == nil && len(returningFields) > 0 {
+		updateQuery.UpdateQuery, err = helper.ComputeReturningQuery(updateQuery.UpdateQuery, returningFields)
+	}
+	if err != nil {
+		return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing returning-query: %v", err.Error()),
+			Value:   nil,
+		})
+	}
 	// perform update action, via transaction:
 	tx, txErr := crud.AppDb.Begin(context.Background())
 	if txErr != nil {
@@ -408,13 +708,45 @@ func (crud *Crud) UpdateById(updateRecs types.ActionParamsType, tableFields []st
 		})
 	}
 	defer tx.Rollback(context.Background())
-	commandTag, updateErr := tx.Exec(context.Background(), updateQuery)
-	if updateErr != nil {
-		_ = tx.Rollback(context.Background())
-		return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
-			Message: fmt.Sprintf("Error updating record(s): %v", updateErr.Error()),
-			Value:   nil,
-		})
+	var updateCount int
+	var returnRecs []interface{}
+	if crud.VersionField != "" {
+		updatedIds, updatedRecs, updateErr := crud.queryUpdatedIds(tx, updateQuery, returningFields)
+		if updateErr != nil {
+			_ = tx.Rollback(context.Background())
+			return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error updating record(s): %v", updateErr.Error()),
+				Value:   nil,
+			})
+		}
+		// version-check conflict: reject the update of all the records
+		if staleIds := helper.ComputeStaleRecordIds(crud.RecordIds, updatedIds); len(staleIds) > 0 {
+			_ = tx.Rollback(context.Background())
+			return crud.conflictMessage(staleIds)
+		}
+		updateCount = len(updatedIds)
+		returnRecs = updatedRecs
+	} else if len(returningFields) > 0 {
+		updatedRecs, updateErr := crud.queryReturningRecords(tx.Query, updateQuery.UpdateQuery, updateQuery.FieldValues, returningFields)
+		if updateErr != nil {
+			_ = tx.Rollback(context.Background())
+			return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error updating record(s): %v", updateErr.Error()),
+				Value:   nil,
+			})
+		}
+		updateCount = len(updatedRecs)
+		returnRecs = updatedRecs
+	} else {
+		commandTag, updateErr := tx.Exec(context.Background(), updateQuery.UpdateQuery, updateQuery.FieldValues...)
+		if updateErr != nil {
+			_ = tx.Rollback(context.Background())
+			return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
+				Message: fmt.Sprintf("Error updating record(s): %v", updateErr.Error()),
+				Value:   nil,
+			})
+		}
+		updateCount = int(commandTag.RowsAffected())
 	}
 	// commit
 	txcErr := tx.Commit(context.Background())
@@ -432,23 +764,62 @@ func (crud *Crud) UpdateById(updateRecs types.ActionParamsType, tableFields []st
 	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
 		Message: "Record(s) update completed successfully",
 		Value: types.CrudResultType{
-			QueryParam:  crud.QueryParams,
-			RecordIds:   crud.RecordIds,
-			RecordCount: int(commandTag.RowsAffected()),
+			QueryParam:   crud.QueryParams,
+			RecordIds:    crud.RecordIds,
+			RecordCount:  updateCount,
+			TableRecords: returnRecs,
 		},
 	})
 }
 
-// UpdateByParam method updates existing records (in batch) that met the specified query-params or where conditions
+// queryUpdatedIds method performs the (RETURNING id) update-query and returns the updated record-ids and, with
+// the returning-fields, the updated records
+func (crud *Crud) queryUpdatedIds(tx pgx.Tx, updateQuery types.UpdateQueryResponseType, returningFields []string) ([]string, []interface{}, error) {
+	rows, err := tx.Query(context.Background(), updateQuery.UpdateQuery, updateQuery.FieldValues...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var updatedIds []string
+	var returnRecs []interface{}
+	for rows.Next() {
+		var updatedId string
+		if len(returningFields) > 0 {
+			returnRec, scanErr := crud.scanReturningRecord(rows.Scan, returningFields, &updatedId)
+			if scanErr != nil {
+				return nil, nil, scanErr
+			}
+			returnRecs = append(returnRecs, returnRec)
+		} else if err = rows.Scan(&updatedId); err != nil {
+			return nil, nil, err
+		}
+		updatedIds = append(updatedIds, updatedId)
+	}
+	return updatedIds, returnRecs, rows.Err()
+}
+
+// UpdateByParam method updates existing records (in batch) that met the specified query-params or where conditions.
+// With the ReturnRecord type, the updated records are returned, as TableRecords
 func (crud *Crud) UpdateByParam(updateRecs types.ActionParamsType, tableFields []string) mcresponse.ResponseMessage {
 	// create from updatedRecs (actionParams)
-	updateQuery, err := helper.ComputeUpdateQueryByParam(crud.TableName, updateRecs, crud.QueryParams, tableFields)
+	updateQuery, err := crud.activeUpdate(helper.ComputeUpdateQueryByParam(crud.TableName, updateRecs, crud.QueryParams, tableFields))
 	if err != nil {
 		return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
 			Message: fmt.Sprintf("Error computing update-query: %v", err.Error()),
 			Value:   nil,
 		})
 	}
+	// returning-fields, to return the updated records
+	returningFields, err := crud.ComputeReturningFields()
+	if err == nil && len(returningFields) > 0 {
+		updateQuery.UpdateQuery, err = helper.ComputeReturningQuery(updateQuery.UpdateQuery, returningFields)
+	}
+	if err != nil {
+		return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
+			Message: fmt.Sprintf("Error computing returning-query: %v", err.Error()),
+			Value:   nil,
+		})
+	}
 	// perform update action, via transaction:
 	tx, txErr := crud.AppDb.Begin(context.Background())
 	if txErr != nil {
@@ -458,7 +829,16 @@ func (crud *Crud) UpdateByParam(updateRecs types.ActionParamsType, tableFields [
 		})
 	}
 	defer tx.Rollback(context.Background())
-	commandTag, updateErr := tx.Exec(context.Background(), updateQuery)
+	var updateCount int
+	var returnRecs []interface{}
+	var updateErr error
+	if len(returningFields) > 0 {
+		returnRecs, updateErr = crud.queryReturningRecords(tx.Query, updateQuery.UpdateQuery, updateQuery.FieldValues, returningFields)
+		updateCount = len(returnRecs)
+	} else {
+		commandTag, execErr := tx.Exec(context.Background(), updateQuery.UpdateQuery, updateQuery.FieldValues...)
+		updateCount, updateErr = int(commandTag.RowsAffected()), execErr
+	}
 	if updateErr != nil {
 		_ = tx.Rollback(context.Background())
 		return mcresponse.GetResMessage("updateError", mcresponse.ResponseMessageOptions{
@@ -482,21 +862,38 @@ func (crud *Crud) UpdateByParam(updateRecs types.ActionParamsType, tableFields [
 	return mcresponse.GetResMessage("success", mcresponse.ResponseMessageOptions{
 		Message: "Record(s) update completed successfully",
 		Value: types.CrudResultType{
-			QueryParam:  crud.QueryParams,
-			RecordIds:   crud.RecordIds,
-			RecordCount: int(commandTag.RowsAffected()),
+			QueryParam:   crud.QueryParams,
+			RecordIds:    crud.RecordIds,
+			RecordCount:  updateCount,
+			TableRecords: returnRecs,
 		},
 	})
 }
 
 func (crud *Crud) UpdateLog(rec interface{}, updateRecs types.ActionParamsType, upTableFields []string) mcresponse.ResponseMessage {
 	// get records to update, for audit-log
-	getRes := crud.GetById(rec)
-	value, _ := getRes.Value.(types.CrudResultType)
-	crud.CurrentRecords = value.TableRecords
+	if crud.LogUpdate {
+		getRes := crud.GetById(rec)
+		value, _ := getRes.Value.(types.CrudResultType)
+		crud.CurrentRecords = value.TableRecords
+	}
 
+	// return the updated records (rec type), for audit-log
+	if crud.LogUpdate && crud.ReturnRecord == nil {
+		crud.ReturnRecord = rec
+		// reset, after the update-log task
+		defer func() { crud.ReturnRecord = nil }()
+	}
 	// perform update
 	updateRes := crud.Update(updateRecs, upTableFields)
+	// the failed update (e.g. the stale records conflictError) is returned, without the audit-log
+	if updateRes.Code != "success" {
+		return updateRes
+	}
+	var newLogRecords interface{} = crud.ActionParams
+	if value, ok := updateRes.Value.(types.CrudResultType); ok && len(value.TableRecords) > 0 {
+		newLogRecords = value.TableRecords
+	}
 
 	// perform audit-log
 	logMessage := ""
@@ -504,7 +901,7 @@ func (crud *Crud) UpdateLog(rec interface{}, updateRecs types.ActionParamsType,
 		auditInfo := mcauditlog.PgxAuditLogOptionsType{
 			TableName:     crud.TableName,
 			LogRecords:    crud.CurrentRecords,
-			NewLogRecords: crud.ActionParams,
+			NewLogRecords: newLogRecords,
 		}
 		if logRes, logErr := crud.TransLog.AuditLog(tasks.Update, crud.UserInfo.UserId, auditInfo); logErr != nil {
 			logMessage = fmt.Sprintf("Audit-log-error: %v", logErr.Error())
@@ -522,12 +919,28 @@ func (crud *Crud) UpdateLog(rec interface{}, updateRecs types.ActionParamsType,
 
 func (crud *Crud) UpdateByIdLog(rec interface{}, updateRecs types.ActionParamsType, upTableFields []string) mcresponse.ResponseMessage {
 	// get records to update, for audit-log
-	getRes := crud.GetById(rec)
-	value, _ := getRes.Value.(types.CrudResultType)
-	crud.CurrentRecords = value.TableRecords
+	if crud.LogUpdate {
+		getRes := crud.GetById(rec)
+		value, _ := getRes.Value.(types.CrudResultType)
+		crud.CurrentRecords = value.TableRecords
+	}
 
+	// return the updated records (rec type), for audit-log
+	if crud.LogUpdate && crud.ReturnRecord == nil {
+		crud.ReturnRecord = rec
+		// reset, after the update-log task
+		defer func() { crud.ReturnRecord = nil }()
+	}
 	// perform update-by-id
 	updateRes := crud.UpdateById(updateRecs, upTableFields)
+	// the failed update (e.g. the stale records conflictError) is returned, without the audit-log
+	if updateRes.Code != "success" {
+		return updateRes
+	}
+	var newLogRecords interface{} = crud.ActionParams
+	if value, ok := updateRes.Value.(types.CrudResultType); ok && len(value.TableRecords) > 0 {
+		newLogRecords = value.TableRecords
+	}
 
 	// perform audit-log
 	logMessage := ""
@@ -535,7 +948,7 @@ func (crud *Crud) UpdateByIdLog(rec interface{}, updateRecs types.ActionParamsTy
 		auditInfo := mcauditlog.PgxAuditLogOptionsType{
 			TableName:     crud.TableName,
 			LogRecords:    crud.CurrentRecords,
-			NewLogRecords: crud.ActionParams,
+			NewLogRecords: newLogRecords,
 		}
 		if logRes, logErr := crud.TransLog.AuditLog(tasks.Update, crud.UserInfo.UserId, auditInfo); logErr != nil {
 			logMessage = fmt.Sprintf("Audit-log-error: %v", logErr.Error())
@@ -551,16 +964,30 @@ func (crud *Crud) UpdateByIdLog(rec interface{}, updateRecs types.ActionParamsTy
 	})
 }
 
-func (crud *Crud) UpdateByParamLog(recParam interface{}, updateRecs types.ActionParamsType, tableFields []string, upTableFields []string, tableFieldPointers []interface{}) mcresponse.ResponseMessage {
+func (crud *Crud) UpdateByParamLog(recParam interface{}, updateRecs types.ActionParamsType, upTableFields []string) mcresponse.ResponseMessage {
 	// get records to update, for audit-log
-	if crud.LogUpdate && len(tableFields) == len(tableFieldPointers) {
+	if crud.LogUpdate {
 		getRes := crud.GetByParam(recParam)
 		value, _ := getRes.Value.(types.CrudResultType)
 		crud.CurrentRecords = value.TableRecords
 	}
 
+	// return the updated records (recParam type), for audit-log
+	if crud.LogUpdate && crud.ReturnRecord == nil {
+		crud.ReturnRecord = recParam
+		// reset, after the update-log task
+		defer func() { crud.ReturnRecord = nil }()
+	}
 	// perform update-by-id
 	updateRes := crud.UpdateByParam(updateRecs, upTableFields)
+	// the failed update (e.g. the stale records conflictError) is returned, without the audit-log
+	if updateRes.Code != "success" {
+		return updateRes
+	}
+	var newLogRecords interface{} = crud.ActionParams
+	if value, ok := updateRes.Value.(types.CrudResultType); ok && len(value.TableRecords) > 0 {
+		newLogRecords = value.TableRecords
+	}
 
 	// perform audit-log
 	logMessage := ""
@@ -568,7 +995,7 @@ func (crud *Crud) UpdateByParamLog(recParam interface{}, updateRecs types.Action
 		auditInfo := mcauditlog.PgxAuditLogOptionsType{
 			TableName:     crud.TableName,
 			LogRecords:    crud.CurrentRecords,
-			NewLogRecords: crud.ActionParams,
+			NewLogRecords: newLogRecords,
 		}
 		if logRes, logErr := crud.TransLog.AuditLog(tasks.Update, crud.UserInfo.UserId, auditInfo); logErr != nil {
 			logMessage = fmt.Sprintf("Audit-log-error: %v", logErr.Error())
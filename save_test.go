@@ -11,7 +11,6 @@ import (
 	"github.com/abbeymart/mcorm/types"
 	"github.com/abbeymart/mctest"
 	"testing"
-	"time"
 )
 
 func TestSave(t *testing.T) {
@@ -35,8 +34,7 @@ func TestSave(t *testing.T) {
 	defer myDb.ClosePgxDbPool()
 	// check db-connection-error
 	if err != nil {
-		fmt.Printf("*****db-connection-error: %v\n", err.Error())
-		return
+		t.Skipf("*****db-connection-error: %v", err.Error())
 	}
 	// expected db-connection result
 	mcLogResult := mcauditlog.PgxLogParam{AuditDb: dbc.DbConn, AuditTable: TestAuditTable}
@@ -99,7 +97,7 @@ func TestSave(t *testing.T) {
 			if !ok {
 				mctest.AssertEquals(t, ok, true, "crud should be instance of mccrud.Crud")
 			}
-			res := crud.Save([]string{})
+			res := crud.Save([]interface{}{CreateRecordA, CreateRecordB})
 			fmt.Println(res.Message, res.ResCode)
 			value, _ := res.Value.(types.CrudResultType)
 			mctest.AssertEquals(t, res.Code, "success", "save-create should return code: success")
@@ -111,7 +109,7 @@ func TestSave(t *testing.T) {
 	mctest.McTest(mctest.OptionValue{
 		Name: "should update two records and return success:",
 		TestFunc: func() {
-			res := updateCrud.Save(UpdateTableFields)
+			res := updateCrud.Save([]interface{}{UpdateRecordA, UpdateRecordB})
 			fmt.Printf("updates: %v : %v \n", res.Message, res.ResCode)
 			mctest.AssertEquals(t, res.Code, "success", "update should return code: success")
 		},
@@ -119,7 +117,7 @@ func TestSave(t *testing.T) {
 	mctest.McTest(mctest.OptionValue{
 		Name: "should update two records by Ids and return success:",
 		TestFunc: func() {
-			res := updateIdCrud.Save(UpdateTableFields)
+			res := updateIdCrud.Save([]interface{}{UpdateRecordById})
 			fmt.Printf("update-by-ids: %v : %v \n", res.Message, res.ResCode)
 			mctest.AssertEquals(t, res.Code, "success", "update-by-id should return code: success")
 		},
@@ -127,7 +125,7 @@ func TestSave(t *testing.T) {
 	mctest.McTest(mctest.OptionValue{
 		Name: "should update two records by query-params and return success:",
 		TestFunc: func() {
-			res := updateParamCrud.Save([]string{})
+			res := updateParamCrud.Save([]interface{}{UpdateRecordByParam})
 			fmt.Printf("update-by-params: %v : %v \n", res.Message, res.ResCode)
 			mctest.AssertEquals(t, res.Code, "success", "update-by-params should return code: success")
 		},
@@ -136,17 +134,7 @@ func TestSave(t *testing.T) {
 	mctest.McTest(mctest.OptionValue{
 		Name: "should update two records, log-task and return success:",
 		TestFunc: func() {
-			var (
-				id            string
-				tableName     string
-				logRecords    interface{}
-				newLogRecords interface{}
-				logBy         string
-				logType       string
-				logAt         time.Time
-			)
-			tableFieldPointers := []interface{}{&id, &tableName, &logRecords, &newLogRecords, &logBy, &logType, &logAt}
-			res := updateCrud.UpdateLog(updateCrud.ActionParams, GetTableFields, UpdateTableFields, tableFieldPointers)
+			res := updateCrud.UpdateLog(GetRecordType{}, updateCrud.ActionParams, UpdateTableFields)
 			fmt.Printf("update-log: %#v \n", res)
 			mctest.AssertEquals(t, res.Code, "success", "update-log should return code: success")
 		},
@@ -154,17 +142,7 @@ func TestSave(t *testing.T) {
 	mctest.McTest(mctest.OptionValue{
 		Name: "should update two records by Ids, log-task and return success:",
 		TestFunc: func() {
-			var (
-				id            string
-				tableName     string
-				logRecords    interface{}
-				newLogRecords interface{}
-				logBy         string
-				logType       string
-				logAt         time.Time
-			)
-			tableFieldPointers := []interface{}{&id, &tableName, &logRecords, &newLogRecords, &logBy, &logType, &logAt}
-			res := updateIdCrud.UpdateByIdLog(updateIdCrud.ActionParams, GetTableFields, UpdateTableFields, tableFieldPointers)
+			res := updateIdCrud.UpdateByIdLog(GetRecordType{}, updateIdCrud.ActionParams, UpdateTableFields)
 			fmt.Printf("update-by-ids-log: %#v \n", res)
 			mctest.AssertEquals(t, res.Code, "success", "update-by-id-log should return code: success")
 		},
@@ -172,17 +150,7 @@ func TestSave(t *testing.T) {
 	mctest.McTest(mctest.OptionValue{
 		Name: "should update two records by query-params, log-task and return success:",
 		TestFunc: func() {
-			var (
-				id            string
-				tableName     string
-				logRecords    interface{}
-				newLogRecords interface{}
-				logBy         string
-				logType       string
-				logAt         time.Time
-			)
-			tableFieldPointers := []interface{}{&id, &tableName, &logRecords, &newLogRecords, &logBy, &logType, &logAt}
-			res := updateParamCrud.UpdateByParamLog(updateParamCrud.ActionParams, GetTableFields, UpdateTableFields, tableFieldPointers)
+			res := updateParamCrud.UpdateByParamLog(GetRecordType{}, updateParamCrud.ActionParams, UpdateTableFields)
 			fmt.Printf("update-by-params-log: %#v \n", res)
 			mctest.AssertEquals(t, res.Code, "success", "update-by-params-log should return code: success")
 		},
@@ -195,25 +163,9 @@ func TestSave(t *testing.T) {
 			if !ok {
 				mctest.AssertEquals(t, ok, true, "crud should be instance of mccrud.Crud")
 			}
-			var (
-				id            string
-				tableName     string
-				logRecords    interface{}
-				newLogRecords interface{}
-				logBy         string
-				logType       string
-				logAt         time.Time
-			)
 			crud.RecordIds = []string{}
 			crud.QueryParams = types.QueryParamType{}
-			tableFieldPointers := []interface{}{&id, &tableName, &logRecords, &newLogRecords, &logBy, &logType, &logAt}
-			// get-record method params
-			saveRecParams := types.SaveCrudParamsType{
-				CreateTableFields:  CreateTableFields,
-				GetTableFields:     GetTableFields,
-				TableFieldPointers: tableFieldPointers,
-			}
-			res := crud.SaveRecord(saveRecParams)
+			res := crud.Save([]interface{}{CreateRecordA, CreateRecordB})
 			fmt.Println(res.Message, res.ResCode)
 			value, _ := res.Value.(types.CrudResultType)
 			mctest.AssertEquals(t, res.Code, "success", "save-create should return code: success")
@@ -224,26 +176,9 @@ func TestSave(t *testing.T) {
 	mctest.McTest(mctest.OptionValue{
 		Name: "should update two records and return success[save-record-method]:",
 		TestFunc: func() {
-			var (
-				id            string
-				tableName     string
-				logRecords    interface{}
-				newLogRecords interface{}
-				logBy         string
-				logType       string
-				logAt         time.Time
-			)
-			tableFieldPointers := []interface{}{&id, &tableName, &logRecords, &newLogRecords, &logBy, &logType, &logAt}
 			updateCrud.RecordIds = []string{}
 			updateCrud.QueryParams = types.QueryParamType{}
-			// get-record method params
-			saveRecParams := types.SaveCrudParamsType{
-				UpdateTableFields:  UpdateTableFields,
-				GetTableFields:     GetTableFields,
-				TableFieldPointers: tableFieldPointers,
-				AuditLog:           true,
-			}
-			res := updateCrud.SaveRecord(saveRecParams)
+			res := updateCrud.UpdateLog(GetRecordType{}, updateCrud.ActionParams, UpdateTableFields)
 			fmt.Printf("update[save-record]: %#v \n", res)
 			mctest.AssertEquals(t, res.Code, "success", "update-log should return code: success")
 		},
@@ -251,26 +186,9 @@ func TestSave(t *testing.T) {
 	mctest.McTest(mctest.OptionValue{
 		Name: "should update two records by Ids and return success[save-record-method]:",
 		TestFunc: func() {
-			var (
-				id            string
-				tableName     string
-				logRecords    interface{}
-				newLogRecords interface{}
-				logBy         string
-				logType       string
-				logAt         time.Time
-			)
-			tableFieldPointers := []interface{}{&id, &tableName, &logRecords, &newLogRecords, &logBy, &logType, &logAt}
 			updateIdCrud.RecordIds = UpdateIds
 			updateIdCrud.QueryParams = types.QueryParamType{}
-			// get-record method params
-			saveRecParams := types.SaveCrudParamsType{
-				UpdateTableFields:  UpdateTableFields,
-				GetTableFields:     GetTableFields,
-				TableFieldPointers: tableFieldPointers,
-				AuditLog:           true,
-			}
-			res := updateIdCrud.SaveRecord(saveRecParams)
+			res := updateIdCrud.UpdateByIdLog(GetRecordType{}, updateIdCrud.ActionParams, UpdateTableFields)
 			fmt.Printf("update-by-ids[save-record]: %#v \n", res)
 			mctest.AssertEquals(t, res.Code, "success", "update-by-id-log should return code: success")
 		},
@@ -278,26 +196,9 @@ func TestSave(t *testing.T) {
 	mctest.McTest(mctest.OptionValue{
 		Name: "should update two records by query-params and return success[save-record-method]:",
 		TestFunc: func() {
-			var (
-				id            string
-				tableName     string
-				logRecords    interface{}
-				newLogRecords interface{}
-				logBy         string
-				logType       string
-				logAt         time.Time
-			)
-			tableFieldPointers := []interface{}{&id, &tableName, &logRecords, &newLogRecords, &logBy, &logType, &logAt}
 			updateParamCrud.RecordIds = []string{}
 			updateParamCrud.QueryParams = UpdateParams
-			// get-record method params
-			saveRecParams := types.SaveCrudParamsType{
-				UpdateTableFields:  UpdateTableFields,
-				GetTableFields:     GetTableFields,
-				TableFieldPointers: tableFieldPointers,
-				AuditLog:           true,
-			}
-			res := updateParamCrud.SaveRecord(saveRecParams)
+			res := updateParamCrud.UpdateByParamLog(GetRecordType{}, updateParamCrud.ActionParams, UpdateTableFields)
 			fmt.Printf("update-by-params[save-record]: %#v \n", res)
 			mctest.AssertEquals(t, res.Code, "success", "update-by-params should return code: success")
 		},
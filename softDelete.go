@@ -0,0 +1,141 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-21 | @Updated: 2021-01-21
+// @Company: mConnect.biz | @License: MIT
+// @Description: soft-delete (deletedAt): read-filter, restore or purge the soft-deleted record(s)
+
+package mcorm
+
+import (
+	"errors"
+	"fmt"
+	"github.com/abbeymart/mcorm/helper"
+	"github.com/abbeymart/mcorm/types"
+	"github.com/abbeymart/mcresponse"
+	"time"
+)
+
+// activeWhere method excludes the soft-deleted records from the (computed) where-query, unless IncludeDeleted
+func (crud *Crud) activeWhere(whereRes types.WhereQueryResponseType, err error) (types.WhereQueryResponseType, error) {
+	if err != nil || crud.SoftDeleteField == "" || crud.IncludeDeleted {
+		return whereRes, err
+	}
+	return helper.ComputeSoftDeleteWhere(whereRes, crud.SoftDeleteField, false)
+}
+
+// activeSelect method excludes the soft-deleted records from the (computed) select-query, unless IncludeDeleted
+func (crud *Crud) activeSelect(selectQuery types.SelectQueryResponseType, err error) (types.SelectQueryResponseType, error) {
+	if err != nil || crud.SoftDeleteField == "" || crud.IncludeDeleted {
+		return selectQuery, err
+	}
+	return helper.ComputeSoftDeleteSelect(selectQuery, crud.SoftDeleteField)
+}
+
+// activeUpdate method excludes the soft-deleted records from the (computed) update-query, unless IncludeDeleted
+func (crud *Crud) activeUpdate(updateQuery types.UpdateQueryResponseType, err error) (types.UpdateQueryResponseType, error) {
+	if err != nil || crud.SoftDeleteField == "" || crud.IncludeDeleted {
+		return updateQuery, err
+	}
+	return helper.ComputeSoftDeleteUpdate(updateQuery, crud.SoftDeleteField)
+}
+
+// computeSoftDeleteQuery method computes the soft-delete (update) query, of the (computed) where-query
+func (crud *Crud) computeSoftDeleteQuery(whereRes types.WhereQueryResponseType, err error) (types.DeleteQueryResponseType, error) {
+	if err != nil {
+		return types.DeleteQueryResponseType{}, err
+	}
+	return helper.ComputeSoftDeleteQuery(crud.TableName, whereRes, crud.SoftDeleteField)
+}
+
+// softDeleteQueryMessage method returns the paramsError response, for the restore or purge tasks without the
+// SoftDeleteField, or for the query computation error
+func (crud *Crud) softDeleteQueryMessage(err error) mcresponse.ResponseMessage {
+	return mcresponse.GetResMessage("paramsError", mcresponse.ResponseMessageOptions{
+		Message: fmt.Sprintf("Error computing soft-delete query: %v", err.Error()),
+		Value:   nil,
+	})
+}
+
+// computeSoftDeleteWhere method computes the where-query, by record-ids or query-params, of the restore or purge
+// tasks, of the soft-delete table
+func (crud *Crud) computeSoftDeleteWhere(byIds bool) (types.WhereQueryResponseType, error) {
+	if crud.SoftDeleteField == "" {
+		return types.WhereQueryResponseType{}, errors.New("soft-delete field is required, for the restore or purge operation")
+	}
+	if byIds {
+		return helper.ComputeWhereQueryByIds(crud.RecordIds, 0)
+	}
+	if len(crud.QueryParams) < 1 {
+		return types.WhereQueryResponseType{}, errors.New("query-params are required, for the restore or purge operation")
+	}
+	return helper.ComputeWhereQuery(crud.QueryParams, 0)
+}
+
+// RestoreById method restores the soft-deleted record(s), by record-id(s)
+func (crud *Crud) RestoreById() mcresponse.ResponseMessage {
+	return crud.restoreRecords(true)
+}
+
+// RestoreByParam method restores the soft-deleted record(s), by query-parameters or where conditions
+func (crud *Crud) RestoreByParam() mcresponse.ResponseMessage {
+	return crud.restoreRecords(false)
+}
+
+// restoreRecords method clears the soft-delete field of the soft-deleted records, by record-ids or query-params
+func (crud *Crud) restoreRecords(byIds bool) mcresponse.ResponseMessage {
+	whereRes, err := crud.computeSoftDeleteWhere(byIds)
+	if err != nil {
+		return crud.softDeleteQueryMessage(err)
+	}
+	restoreQuery, err := helper.ComputeRestoreQuery(crud.TableName, whereRes, crud.SoftDeleteField)
+	if err != nil {
+		return crud.softDeleteQueryMessage(err)
+	}
+	restoreRes := crud.deleteRecords(restoreQuery, nil)
+	if restoreRes.Code == "success" {
+		restoreRes.Message = "Record(s) restored successfully"
+	}
+	return restoreRes
+}
+
+// PurgeById method permanently deletes the soft-deleted record(s), by record-id(s)
+func (crud *Crud) PurgeById() mcresponse.ResponseMessage {
+	whereRes, err := crud.computeSoftDeleteWhere(true)
+	if err != nil {
+		return crud.softDeleteQueryMessage(err)
+	}
+	return crud.purgeRecords(helper.ComputePurgeQuery(crud.TableName, whereRes, crud.SoftDeleteField))
+}
+
+// PurgeByParam method permanently deletes the soft-deleted record(s), by query-parameters or where conditions
+func (crud *Crud) PurgeByParam() mcresponse.ResponseMessage {
+	whereRes, err := crud.computeSoftDeleteWhere(false)
+	if err != nil {
+		return crud.softDeleteQueryMessage(err)
+	}
+	return crud.purgeRecords(helper.ComputePurgeQuery(crud.TableName, whereRes, crud.SoftDeleteField))
+}
+
+// PurgeDeleted method permanently deletes the record(s) soft-deleted before the deletedBefore time, e.g. after
+// the retention period
+func (crud *Crud) PurgeDeleted(deletedBefore time.Time) mcresponse.ResponseMessage {
+	if crud.SoftDeleteField == "" {
+		return crud.softDeleteQueryMessage(errors.New("soft-delete field is required, for the purge operation"))
+	}
+	return crud.purgeRecords(helper.ComputePurgeBeforeQuery(crud.TableName, crud.SoftDeleteField, deletedBefore))
+}
+
+// purgeRecords method performs the (computed) purge-query, and the referential-integrity (OnDelete) actions of the
+// child-relations
+func (crud *Crud) purgeRecords(purgeQuery types.DeleteQueryResponseType, err error) mcresponse.ResponseMessage {
+	if err != nil {
+		return crud.softDeleteQueryMessage(err)
+	}
+	actionQueries, err := helper.ComputeDeleteActionQueries(crud.TableName, crud.ChildRelations, purgeQuery.WhereQuery, false)
+	if err != nil {
+		return crud.softDeleteQueryMessage(err)
+	}
+	purgeRes := crud.deleteRecords(purgeQuery, actionQueries)
+	if purgeRes.Code == "success" {
+		purgeRes.Message = "Record(s) purged successfully"
+	}
+	return purgeRes
+}
@@ -0,0 +1,13 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-13 | @Updated: 2021-01-13
+// @Company: mConnect.biz | @License: MIT
+// @Description: aggregate operator constants
+
+package aggregateOperators
+
+const (
+	Count = "count"
+	Sum   = "sum"
+	Avg   = "avg"
+	Min   = "min"
+	Max   = "max"
+)
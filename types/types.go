@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"github.com/abbeymart/mcorm/types/datatypes"
 	"github.com/abbeymart/mctypes"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"go.mongodb.org/mongo-driver/mongo"
 	"time"
@@ -54,9 +55,26 @@ type ValueToDataType map[string]interface{}
 type ActionParamsType []ActionParamType
 type ExistParamType map[string]interface{}
 type ExistParamsType []ExistParamType
-type SortParamType map[string]int     // 1 for "asc", -1 for "desc"
+type SortItemType struct {
+	FieldName  string `json:"fieldName"`
+	SortOrder  int    `json:"sortOrder"`  // 1 for "asc", -1 for "desc"
+	NullsOrder string `json:"nullsOrder"` // "first" or "last", db-default if not specified
+}
+type SortParamType []SortItemType     // ordered by sort-priority
 type ProjectParamType map[string]bool // 1 or true for inclusion, 0 or false for exclusion
 
+type AggregateItemType struct {
+	FieldName string `json:"fieldName"` // "*" for count of all records
+	Operator  string `json:"operator"`  // count, sum, avg, min or max
+	Alias     string `json:"alias"`     // result field-name, default: operator_fieldName, e.g. sum_amount | count_all
+}
+
+type AggregateParamType struct {
+	Aggregates   []AggregateItemType `json:"aggregates"`
+	GroupFields  []string            `json:"groupFields"`
+	HavingParams QueryParamType      `json:"havingParams"` // having-conditions, by aggregate-aliases or group-fields
+}
+
 type QueryItemType struct {
 	GroupItem      map[string]map[string]interface{} `json:"groupItem"`      // key1 => fieldName, key2 => fieldOperator, interface{}=> value(s)
 	GroupItemOrder int                               `json:"groupItemOrder"` // item/field order within the group
@@ -75,19 +93,23 @@ type WhereParamType []QueryGroupType
 
 // CrudParamsType is the struct type for receiving, composing and passing CRUD inputs
 type CrudParamsType struct {
-	AppDb         *pgxpool.Pool        `json:"-"`
-	TableName     string               `json:"-"`
-	UserInfo      mctypes.UserInfoType `json:"userInfo"`
-	ActionParams  ActionParamsType     `json:"actionParams"`
-	ExistParams   ExistParamsType      `json:"existParams"`
-	QueryParams   QueryParamType       `json:"queryParams"`
-	RecordIds     []string             `json:"recordIds"`
-	ProjectParams ProjectParamType     `json:"projectParams"`
-	SortParams    SortParamType        `json:"sortParams"`
-	Token         string               `json:"token"`
-	Skip          int                  `json:"skip"`
-	Limit         int                  `json:"limit"`
-	TaskType      string               `json:"-"`
+	AppDb           *pgxpool.Pool        `json:"-"`
+	TableName       string               `json:"-"`
+	UserInfo        mctypes.UserInfoType `json:"userInfo"`
+	ActionParams    ActionParamsType     `json:"actionParams"`
+	ExistParams     ExistParamsType      `json:"existParams"`
+	QueryParams     QueryParamType       `json:"queryParams"`
+	RecordIds       []string             `json:"recordIds"`
+	ProjectParams   ProjectParamType     `json:"projectParams"`
+	SortParams      SortParamType        `json:"sortParams"`
+	Token           string               `json:"token"`
+	Skip            int                  `json:"skip"`
+	Limit           int                  `json:"limit"`
+	AggregateParams AggregateParamType   `json:"aggregateParams"`
+	CursorPaging    bool                 `json:"cursorPaging"` // keyset (cursor) pagination, by the sortParams, instead of skip/offset
+	Cursor          string               `json:"cursor"`       // next-cursor from the previous page, empty for the first page
+	UpsertParams    UpsertParamType      `json:"upsertParams"` // create-batch upsert (INSERT ... ON CONFLICT) mode
+	TaskType        string               `json:"-"`
 }
 
 type CrudOptionsType struct {
@@ -105,6 +127,17 @@ type CrudOptionsType struct {
 	AccessTable           string
 	VerifyTable           string
 	UserProfileTable      string
+	RecordDesc            RecordDescType       // model record-description, for field-names validation
+	PrimaryFields         []string             // model primary-fields, default upsert conflict-target
+	UniqueFields          UniqueFieldsType     // model unique-fields, default upsert conflict-target, if no primary-fields
+	CopyThreshold         int                  // create-records count, from which save creates via COPY | default: 1000
+	CopyChunkSize         int                  // COPY records per chunk | default: 5000
+	CopyProgress          CopyProgressFuncType // COPY progress callback, after each chunk
+	VersionField          string               // optimistic-concurrency version table-field, checked by updates
+	ReturnRecord          interface{}          // struct{} record-type: the affected (create/update/delete) rows are returned, as TableRecords
+	SoftDeleteField       string               // soft-delete table-field (e.g. deleted_at): deletes set the field, reads exclude the deleted records
+	IncludeDeleted        bool                 // reads include the soft-deleted records
+	ChildRelations        []ModelRelationType  // model child-relations, for the referential-integrity (OnDelete) actions
 	MaxQueryLimit         int
 	LogCrud               bool
 	LogCreate             bool
@@ -289,10 +322,31 @@ type CreateQueryResponseType struct {
 	FieldValues [][]interface{}
 }
 
+// CopyQueryResponseType describes the COPY (temp-table) scripts of the copy-create task
+type CopyQueryResponseType struct {
+	CopyTable   string // temp-table, COPY target
+	CreateQuery string // create temp-table, dropped on commit
+	InsertQuery string // insert the copied records into the table, returning the generated ids
+	ClearQuery  string // clear the temp-table, for the next chunk
+}
+
+// CopyProgressFuncType reports the COPY progress, after each chunk
+type CopyProgressFuncType func(copiedCount int, totalCount int)
+
+// RelationActionQueryType is the referential-integrity (OnDelete) action-query of the child-relation
+type RelationActionQueryType struct {
+	RelationName string
+	ChildTable   string
+	Action       string // ormActions: restrict (exists-check query), cascade, null or default
+	ActionQuery  string
+}
+
 type UpdateQueryResponseType struct {
 	UpdateQuery string
 	WhereQuery  string
 	FieldValues []interface{}
+	FieldNames  []string        // update-fields, by placeholder order
+	RowValues   [][]interface{} // per-record placeholder-values, for the reusable update-query
 }
 
 type WhereQueryResponseType struct {
@@ -386,10 +440,28 @@ func (err ErrorType) Error() string {
 }
 
 type CrudResultType struct {
-	QueryParam   QueryParamType `json:"queryParam"`
-	RecordIds    []string       `json:"recordIds"`
-	RecordCount  int            `json:"recordCount"`
-	TableRecords []interface{}  `json:"tableRecords"`
+	QueryParam     QueryParamType     `json:"queryParam"`
+	RecordIds      []string           `json:"recordIds"`
+	RecordCount    int                `json:"recordCount"`
+	TotalCount     int                `json:"totalCount"` // total records count, for paginated (skip, limit or cursor) queries
+	TableRecords   []interface{}      `json:"tableRecords"`
+	NextCursor     string             `json:"nextCursor"`     // for keyset (cursor) pagination, empty for the last page
+	UpsertResults  []UpsertResultType `json:"upsertResults"`  // per-record upsert result, in the create-records order
+	StaleRecordIds []string           `json:"staleRecordIds"` // optimistic-concurrency conflict: the stale (changed) records
+}
+
+// UpsertParamType describes the upsert (INSERT ... ON CONFLICT) mode of the create-batch task
+type UpsertParamType struct {
+	Upsert         bool     `json:"upsert"`
+	ConflictFields []string `json:"conflictFields"` // conflict-target | default: primary-fields or unique-fields
+	UpdateFields   []string `json:"updateFields"`   // DO UPDATE fields | default: table-fields, except the conflict-fields
+	DoNothing      bool     `json:"doNothing"`      // ON CONFLICT DO NOTHING, the conflicting records are skipped
+}
+
+type UpsertResultType struct {
+	RecordId string `json:"recordId"`
+	Inserted bool   `json:"inserted"` // false for the updated and the skipped records
+	Skipped  bool   `json:"skipped"`  // DO NOTHING conflicting record, without recordId
 }
 
 type LogRecordsType struct {
@@ -416,6 +488,7 @@ type ComputedMethodsType map[string]ComputedValueType
 
 type FieldDescType struct {
 	FieldType       string
+	FormerName      string // previous field-name, to rename the table column (alter-table)
 	FieldLength     int    // default: 255 for DataType.STRING
 	FieldPattern    string // "/^[0-9]{10}$/" => includes 10 digits, 0 to 9 | "/^[0-9]{6}.[0-9]{2}$/ => max 16 digits and 2 decimal places
 	AllowNull       bool   // default: true
@@ -432,6 +505,7 @@ type FieldDescType struct {
 }
 
 type ModelRelationType struct {
+	RelationName  string // relation name, for eager-loading (include) and nesting of related records | default: targetTable
 	SourceTable   string
 	TargetTable   string
 	SourceField   string
@@ -458,7 +532,12 @@ type ModelType struct {
 	ComputedMethods  ComputedMethodsType	// model-level functions, e.g fullName(a, b: T): T
 	ValidateMethods  ValidateMethodsType
 	AlterSyncTable   bool	// create / alter table/collection and sync existing data, if there was a change to the table structure | default: true
-	// if alterSyncTable: false it will create/re-create the table, with no data sync
+	// if alterSyncTable: false, with RecreateTable, it will create/re-create the table, with no data sync
+	VersionStamp     bool	// auto-add: version (optimistic concurrency), checked and incremented by updates | default: false
+	VersionField     string	// optimistic-concurrency field, e.g. updatedAt (timestamp check) | default: version, for VersionStamp
+	SoftDelete       bool	// auto-add: deletedAt; deletes set deletedAt and reads exclude the deleted records | default: false
+	DropColumns      bool	// alter-table drops the table columns not in the model (data loss) | default: false
+	RecreateTable    bool	// sync-table drops and re-creates the table, for AlterSyncTable: false (data loss) | default: false
 }
 
 type UniqueFieldsType [][]string
@@ -479,42 +558,84 @@ type ModelCrudOptionsType struct {
 	RequiredFields []string            // may be computed from FieldDesc allowNull attributes
 }
 
+// migration types
+
+type MigrationFuncType func(tx pgx.Tx) error
+
+type MigrationType struct {
+	Version int64  // ordered, unique migration version, e.g. 20210115120000
+	Name    string
+	UpSql   string
+	DownSql string
+	Up      MigrationFuncType // Go-function migration, applied after the UpSql, if specified
+	Down    MigrationFuncType // Go-function rollback, applied after the DownSql, if specified
+}
+
+type MigrationHistoryType struct {
+	Version   int64     `json:"version"`
+	Name      string    `json:"name"`
+	Checksum  string    `json:"checksum"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+type SeedFuncType func(appDb *pgxpool.Pool) error
+
+type SeedType struct {
+	Name string
+	Seed SeedFuncType
+}
+
 func defaultLanguage() interface{}  { return "en-US" }
 func defaultIsActive() interface{}  { return true }
 func defaultTimeStamp() interface{} { return time.Now() }
+func defaultVersion() interface{}   { return 1 }
 
 var BaseModel = RecordDescType{
 	"id": FieldDescType{
-		FieldType: datatypes.UUID,
+		FieldType:  datatypes.UUID,
+		PrimaryKey: true,
 	},
 	"language": FieldDescType{
-		FieldType:    datatypes.UUID,
+		FieldType:    datatypes.String,
 		FieldLength:  12,
 		AllowNull:    false,
 		DefaultValue: defaultLanguage,
 	},
 	"desc": FieldDescType{
 		FieldType: datatypes.String,
+		AllowNull: true,
 	},
 	"isActive": FieldDescType{
-		FieldType:    datatypes.UUID,
+		FieldType:    datatypes.Boolean,
 		AllowNull:    false,
 		DefaultValue: defaultIsActive,
 	},
 	"createdBy": FieldDescType{
 		FieldType: datatypes.UUID,
+		AllowNull: true,
 	},
 	"updatedBy": FieldDescType{
 		FieldType: datatypes.UUID,
+		AllowNull: true,
 	},
 	"createdAt": FieldDescType{
 		FieldType:    datatypes.DateTime,
 		DefaultValue: defaultTimeStamp,
 	},
+	"updatedAt": FieldDescType{
+		FieldType:    datatypes.DateTime,
+		DefaultValue: defaultTimeStamp,
+	},
+	"version": FieldDescType{
+		FieldType:    datatypes.BigInt,
+		DefaultValue: defaultVersion,
+	},
 	"deletedAt": FieldDescType{
 		FieldType: datatypes.DateTime,
+		AllowNull: true,
 	},
 	"appId": FieldDescType{
 		FieldType: datatypes.String,
+		AllowNull: true,
 	},
 }
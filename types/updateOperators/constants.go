@@ -0,0 +1,16 @@
+// @Author: abbeymart | Abi Akindele | @Created: 2021-01-20 | @Updated: 2021-01-20
+// @Company: mConnect.biz | @License: MIT
+// @Description: update-operator constants, for the atomic field-updates, e.g. {"stock": {"$inc": -1}}
+
+package updateOperators
+
+const (
+	Inc       = "$inc"       // field + value
+	Dec       = "$dec"       // field - value
+	Mul       = "$mul"       // field * value
+	Append    = "$append"    // append the value (element) to the array-field
+	Remove    = "$remove"    // remove the value (all element occurrences) from the array-field
+	JsonSet   = "$jsonSet"   // set the jsonb-field value at the path: {"path": "a.b" | []string{"a", "b"}, "value": v}
+	JsonMerge = "$jsonMerge" // merge the value (object) into the jsonb-field
+	Now       = "$now"       // set the field to the current timestamp, e.g. {"$now": true}
+)